@@ -3,9 +3,11 @@ package cli
 import (
 	"fmt"
 	"os"
+	"os/exec"
 	"path"
 
 	log "github.com/Sirupsen/logrus"
+	"github.com/bitrise-tools/releaseman/git"
 	"github.com/bitrise-tools/releaseman/releaseman"
 	"github.com/bitrise-tools/releaseman/version"
 	"github.com/codegangsta/cli"
@@ -24,6 +26,36 @@ func before(c *cli.Context) error {
 		releaseman.IsCIMode = true
 	}
 
+	// Git backend
+	gitBackend := c.String(GitBackendKey)
+	if gitBackend == "" {
+		gitBackend = ExecGitBackend
+		if _, err := exec.LookPath("git"); err != nil {
+			gitBackend = GoGitBackend
+		}
+	}
+	log.Debugf("Git backend: %s", gitBackend)
+
+	switch gitBackend {
+	case ExecGitBackend:
+		git.SetRepository(git.NewExecRepository())
+	case GoGitBackend:
+		repository, err := git.OpenGoGitRepository(".")
+		if err != nil {
+			return err
+		}
+		git.SetRepository(repository)
+	default:
+		return fmt.Errorf("Invalid git backend (%s), options: %s, %s", gitBackend, ExecGitBackend, GoGitBackend)
+	}
+
+	// Dry run mode
+	if c.Bool(DryRunKey) {
+		releaseman.IsDryRun = true
+		git.SetRepository(git.NewDryRunRepository(git.CurrentRepository(), releaseman.DryRunChangedFiles))
+		log.Warnf("Dry run: the files and the repository are not changed")
+	}
+
 	return nil
 }
 
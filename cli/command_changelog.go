@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"fmt"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/bitrise-io/go-utils/colorstring"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/bitrise-io/goinp/goinp"
+	"github.com/bitrise-tools/releaseman/git"
+	"github.com/bitrise-tools/releaseman/releaseman"
+	"github.com/codegangsta/cli"
+)
+
+//=======================================
+// Utility
+//=======================================
+
+func collectRebuildChangelogConfigParams(config releaseman.Config, c *cli.Context) (releaseman.Config, error) {
+	var err error
+
+	//
+	// Fill component
+	if config, err = fillComponent(config, c); err != nil {
+		return releaseman.Config{}, err
+	}
+
+	//
+	// Fill changelog path filter
+	config = fillChangelogPathFilter(config, c)
+
+	//
+	// Fill development branch
+	if config, err = fillDevelopmetnBranch(config, c); err != nil {
+		return releaseman.Config{}, err
+	}
+
+	//
+	// Ensure current branch
+	if err := ensureCurrentBranch(config); err != nil {
+		return releaseman.Config{}, err
+	}
+
+	//
+	// Fill release branch, the release tags on it are listed too
+	if c.IsSet(ReleaseBranchKey) {
+		config.Release.ReleaseBranch = c.String(ReleaseBranchKey)
+	}
+
+	//
+	// Fill changelog path
+	if config, err = fillChangelogPath(config, c); err != nil {
+		return releaseman.Config{}, err
+	}
+
+	return config, nil
+}
+
+func regenerateChangelog(config releaseman.Config) error {
+	scheme, err := config.VersionScheme()
+	if err != nil {
+		return err
+	}
+
+	taggedCommits, err := git.BranchVersionTaggedCommits(config.Release.TagFormat, scheme, config.Release.DevelopmentBranch, config.Release.ReleaseBranch)
+	if err != nil {
+		return fmt.Errorf("Failed to get tagged commits, error: %s", err)
+	}
+
+	fmt.Println()
+	log.Infof("Collecting commits of %d releases", len(taggedCommits))
+
+	fmt.Println()
+	log.Infof("=> Rebuilding Changelog...")
+	commits, err := git.GetCommitsFrom(nil)
+	if err != nil {
+		return fmt.Errorf("Failed to get commits, error: %s", err)
+	}
+	if err := releaseman.RebuildChangelog(commits, taggedCommits, config); err != nil {
+		return fmt.Errorf("Failed to write Changelog, error: %s", err)
+	}
+
+	return nil
+}
+
+//=======================================
+// Main
+//=======================================
+
+func rebuildChangelog(c *cli.Context) {
+	//
+	// Build config
+	config := releaseman.Config{}
+	configPath := ""
+	if c.IsSet("config") {
+		configPath = c.String("config")
+	} else {
+		configPath = releaseman.DefaultConfigPth
+	}
+
+	if exist, err := pathutil.IsPathExists(configPath); err != nil {
+		log.Warnf("Failed to check if path exist, error: %#v", err)
+	} else if exist {
+		config, err = releaseman.NewConfigFromFile(configPath)
+		if err != nil {
+			log.Fatalf("Failed to parse release config at (%s), error: %#v", configPath, err)
+		}
+	}
+
+	config, err := collectRebuildChangelogConfigParams(config, c)
+	if err != nil {
+		log.Fatalf("Failed to collect config params, error: %s", err)
+	}
+
+	//
+	// Validate config
+	config.Print(releaseman.ChangelogMode)
+
+	if !releaseman.IsCIMode && !releaseman.IsDryRun {
+		ok, err := goinp.AskForBoolWithDefault(fmt.Sprintf("Are you ready for overwriting the Changelog (%s)?", config.Changelog.Path), true)
+		if err != nil {
+			log.Fatalf("Failed to ask for input, error: %s", err)
+		}
+		if !ok {
+			log.Fatal("Aborted rebuild Changelog")
+		}
+	}
+
+	//
+	// Rebuild Changelog
+	if err := regenerateChangelog(config); err != nil {
+		log.Fatal(err)
+	}
+
+	if releaseman.IsDryRun {
+		printDryRunSummary(config)
+		return
+	}
+
+	fmt.Println()
+	log.Infoln(colorstring.Greenf("Changelog rebuilt (%s) 🚀", config.Changelog.Path))
+}
@@ -18,6 +18,16 @@ import (
 func collectConfigParams(config releaseman.Config, c *cli.Context) (releaseman.Config, error) {
 	var err error
 
+	//
+	// Fill component
+	if config, err = fillComponent(config, c); err != nil {
+		return releaseman.Config{}, err
+	}
+
+	//
+	// Fill changelog path filter
+	config = fillChangelogPathFilter(config, c)
+
 	//
 	// Fill development branch
 	if config, err = fillDevelopmetnBranch(config, c); err != nil {
@@ -42,6 +52,17 @@ func collectConfigParams(config releaseman.Config, c *cli.Context) (releaseman.C
 		return releaseman.Config{}, err
 	}
 
+	//
+	// Fill push
+	if config, err = fillPush(config, c); err != nil {
+		return releaseman.Config{}, err
+	}
+	if config.Release.Push {
+		if err := ensureNotBehindRemote(config); err != nil {
+			return releaseman.Config{}, err
+		}
+	}
+
 	//
 	// Fill changelog path
 	if config, err = fillChangelogPath(config, c); err != nil {
@@ -59,7 +80,7 @@ func create(c *cli.Context) {
 	//
 	// Fail if git is not clean
 	if err := ensureCleanGit(); err != nil {
-		log.Fatalf("Ensure clean git failed, error: %#v", err)
+		log.Fatalf("Ensure clean git failed, error: %s", err)
 	}
 
 	//
@@ -83,16 +104,14 @@ func create(c *cli.Context) {
 
 	config, err := collectConfigParams(config, c)
 	if err != nil {
-		log.Fatalf("Failed to collect config params, error: %#v", err)
+		log.Fatalf("Failed to collect config params, error: %s", err)
 	}
 
-	printRollBackMessage()
-
 	//
 	// Validate config
 	config.Print(releaseman.FullMode)
 
-	if !releaseman.IsCIMode {
+	if !releaseman.IsCIMode && !releaseman.IsDryRun {
 		ok, err := goinp.AskForBoolWithDefault("Are you ready for release?", true)
 		if err != nil {
 			log.Fatalf("Failed to ask for input, error: %s", err)
@@ -103,23 +122,59 @@ func create(c *cli.Context) {
 	}
 
 	//
-	// Run set version script
-	if c.IsSet(SetVersionScriptKey) {
-		setVersionScript := c.String(SetVersionScriptKey)
-		if err := runSetVersionScript(setVersionScript, config.Release.Version); err != nil {
-			log.Fatalf("Failed to run set version script, error: %#v", err)
-		}
+	// Save the state to roll back to
+	state, err := captureReleaseState(config, true)
+	if err != nil {
+		log.Fatalf("Failed to save the repository state, error: %s", err)
+	}
+
+	//
+	// Set version
+	if err := setVersion(config, c); err != nil {
+		rollbackRelease(state)
+		log.Fatalf("Failed to set version, error: %s", err)
 	}
 
 	//
 	// Generate Changelog
-	generateChangelog(config)
+	if err := generateChangelog(config); err != nil {
+		rollbackRelease(state)
+		log.Fatal(err)
+	}
 
 	//
 	// Create release git changes
-	generateRelease(config)
+	if err := generateRelease(config); err != nil {
+		rollbackRelease(state)
+		log.Fatalf("Failed to create release, error: %s", err)
+	}
+
+	//
+	// Push the release
+	if config.Release.Push {
+		if err := pushRelease(config); err != nil {
+			rollbackRelease(state)
+			log.Fatalf("Failed to push release, error: %s", err)
+		}
+	}
+
+	if releaseman.IsDryRun {
+		printDryRunSummary(config)
+		return
+	}
+
+	if config.Release.Push {
+		fmt.Println()
+		log.Infoln(colorstring.Greenf("v%s released and pushed to %s 🚀", config.Release.Version, config.Release.Remote))
+		return
+	}
+
+	if err := recordRelease(config, state); err != nil {
+		log.Warnf("Failed to record the release, it can not be rolled back by releaseman, error: %s", err)
+	}
 
 	fmt.Println()
 	log.Infoln(colorstring.Greenf("v%s released 🚀", config.Release.Version))
 	log.Infoln("Take a look at your git, and if you are happy with the release, push the changes.")
+	log.Infoln("To undo the release call: releaseman rollback")
 }
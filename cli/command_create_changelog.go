@@ -19,6 +19,16 @@ import (
 func collectChangelogConfigParams(config releaseman.Config, c *cli.Context) (releaseman.Config, error) {
 	var err error
 
+	//
+	// Fill component
+	if config, err = fillComponent(config, c); err != nil {
+		return releaseman.Config{}, err
+	}
+
+	//
+	// Fill changelog path filter
+	config = fillChangelogPathFilter(config, c)
+
 	//
 	// Fill development branch
 	if config, err = fillDevelopmetnBranch(config, c); err != nil {
@@ -33,7 +43,11 @@ func collectChangelogConfigParams(config releaseman.Config, c *cli.Context) (rel
 
 	//
 	// Fill release version
-	if config, err = fillVersion(config, c); err != nil {
+	if c.Bool(UnreleasedKey) {
+		if config.Changelog.Format != releaseman.KeepAChangelogFormat {
+			return releaseman.Config{}, fmt.Errorf("The Unreleased section is supported by the %s changelog format only", releaseman.KeepAChangelogFormat)
+		}
+	} else if config, err = fillVersion(config, c); err != nil {
 		return releaseman.Config{}, err
 	}
 
@@ -46,10 +60,20 @@ func collectChangelogConfigParams(config releaseman.Config, c *cli.Context) (rel
 	return config, nil
 }
 
-func generateChangelog(config releaseman.Config) {
-	taggedCommits, err := git.VersionTaggedCommits()
+func generateChangelog(config releaseman.Config) error {
+	scheme, err := config.VersionScheme()
+	if err != nil {
+		return err
+	}
+
+	allTaggedCommits, err := git.VersionTaggedCommits(config.Release.TagFormat, scheme)
 	if err != nil {
-		log.Fatalf("Failed to get tagged commits, error: %#v", err)
+		return fmt.Errorf("Failed to get tagged commits, error: %s", err)
+	}
+	// the release tags are on the release branch (e.g. on its merge commits), which the development branch may not reach
+	taggedCommits, err := git.BranchVersionTaggedCommits(config.Release.TagFormat, scheme, config.Release.DevelopmentBranch, config.Release.ReleaseBranch)
+	if err != nil {
+		return fmt.Errorf("Failed to get tagged commits, error: %s", err)
 	}
 
 	var startCommitPtr *git.CommitModel
@@ -63,16 +87,18 @@ func generateChangelog(config releaseman.Config) {
 
 	if config.Changelog.Path != "" {
 		if exist, err := pathutil.IsPathExists(config.Changelog.Path); err != nil {
-			log.Fatalf("Failed to check if path exist, error: %#v", err)
+			return fmt.Errorf("Failed to check if path exist, error: %s", err)
 		} else if exist {
+			// the keepachangelog format merges the releases into the existing changelog,
+			// an existing changelog of a tagged repository is never overwritten, even if no tag is reachable
+			appendChangelog = config.Changelog.Format == releaseman.KeepAChangelogFormat || len(allTaggedCommits) > 0
+
 			if len(taggedCommits) > 0 {
 				lastTaggedCommit := taggedCommits[len(taggedCommits)-1]
 
 				startCommitPtr = &lastTaggedCommit
 
 				relevantTags = []git.CommitModel{lastTaggedCommit}
-
-				appendChangelog = true
 			}
 		}
 	}
@@ -83,11 +109,52 @@ func generateChangelog(config releaseman.Config) {
 	log.Infof("=> Generating Changelog...")
 	commits, err := git.GetCommitsFrom(startCommitPtr)
 	if err != nil {
-		log.Fatalf("Failed to get commits, error: %#v", err)
+		return fmt.Errorf("Failed to get commits, error: %s", err)
 	}
 	if err := releaseman.WriteChangelog(commits, relevantTags, config, appendChangelog); err != nil {
-		log.Fatalf("Failed to write Changelog, error: %#v", err)
+		return fmt.Errorf("Failed to write Changelog, error: %s", err)
+	}
+
+	if len(config.Changelog.StoreNotes) > 0 && config.Release.Version != "" {
+		if err := generateStoreNotes(config, taggedCommits); err != nil {
+			return fmt.Errorf("Failed to write store notes, error: %s", err)
+		}
+	}
+
+	return nil
+}
+
+// generateStoreNotes writes the store notes of the commits since the last release.
+func generateStoreNotes(config releaseman.Config, taggedCommits []git.CommitModel) error {
+	var lastTaggedCommitPtr *git.CommitModel
+	if len(taggedCommits) > 0 {
+		lastTaggedCommitPtr = &(taggedCommits[len(taggedCommits)-1])
+	}
+
+	commits, err := git.GetCommitsFrom(lastTaggedCommitPtr)
+	if err != nil {
+		return fmt.Errorf("Failed to get commits, error: %s", err)
+	}
+	releaseNotes := releaseman.NewReleaseNotes(commits, lastTaggedCommitPtr, config)
+
+	versionCode, err := releaseman.AndroidVersionCode(config.Release.VersionFiles)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+	log.Infof("=> Generating store notes...")
+	for _, notes := range config.Changelog.StoreNotes {
+		paths, err := releaseman.WriteStoreNotes(releaseNotes, notes, versionCode)
+		if err != nil {
+			return err
+		}
+		for _, pth := range paths {
+			log.Infof(" * %s", pth)
+		}
 	}
+
+	return nil
 }
 
 //=======================================
@@ -116,14 +183,14 @@ func createChangelog(c *cli.Context) {
 
 	config, err := collectChangelogConfigParams(config, c)
 	if err != nil {
-		log.Fatalf("Failed to collect config params, error: %#v", err)
+		log.Fatalf("Failed to collect config params, error: %s", err)
 	}
 
 	//
 	// Validate config
 	config.Print(releaseman.ChangelogMode)
 
-	if !releaseman.IsCIMode {
+	if !releaseman.IsCIMode && !releaseman.IsDryRun {
 		ok, err := goinp.AskForBoolWithDefault("Are you ready for creating Changelog?", true)
 		if err != nil {
 			log.Fatalf("Failed to ask for input, error: %s", err)
@@ -134,18 +201,28 @@ func createChangelog(c *cli.Context) {
 	}
 
 	//
-	// Run set version script
-	if c.IsSet(SetVersionScriptKey) {
-		setVersionScript := c.String(SetVersionScriptKey)
-		if err := runSetVersionScript(setVersionScript, config.Release.Version); err != nil {
-			log.Fatalf("Failed to run set version script, error: %#v", err)
+	// Set version
+	if config.Release.Version != "" {
+		if err := setVersion(config, c); err != nil {
+			log.Fatalf("Failed to set version, error: %s", err)
 		}
 	}
 
 	//
 	// Generate Changelog
-	generateChangelog(config)
+	if err := generateChangelog(config); err != nil {
+		log.Fatal(err)
+	}
+
+	if releaseman.IsDryRun {
+		printDryRunSummary(config)
+		return
+	}
 
 	fmt.Println()
-	log.Infoln(colorstring.Greenf("v%s Changelog created (%s) 🚀", config.Release.Version, config.Changelog.Path))
+	if config.Release.Version == "" {
+		log.Infoln(colorstring.Greenf("Unreleased changes added to the Changelog (%s) 🚀", config.Changelog.Path))
+	} else {
+		log.Infoln(colorstring.Greenf("v%s Changelog created (%s) 🚀", config.Release.Version, config.Changelog.Path))
+	}
 }
@@ -2,9 +2,11 @@ package cli
 
 import (
 	"fmt"
+	"os"
 
 	log "github.com/Sirupsen/logrus"
 	"github.com/bitrise-io/go-utils/colorstring"
+	"github.com/bitrise-io/go-utils/fileutil"
 	"github.com/bitrise-io/go-utils/pathutil"
 	"github.com/bitrise-io/goinp/goinp"
 	"github.com/bitrise-tools/releaseman/git"
@@ -19,6 +21,16 @@ import (
 func collectReleaseConfigParams(config releaseman.Config, c *cli.Context) (releaseman.Config, error) {
 	var err error
 
+	//
+	// Fill component
+	if config, err = fillComponent(config, c); err != nil {
+		return releaseman.Config{}, err
+	}
+
+	//
+	// Fill changelog path filter
+	config = fillChangelogPathFilter(config, c)
+
 	//
 	// Fill development branch
 	if config, err = fillDevelopmetnBranch(config, c); err != nil {
@@ -43,40 +55,264 @@ func collectReleaseConfigParams(config releaseman.Config, c *cli.Context) (relea
 		return releaseman.Config{}, err
 	}
 
+	//
+	// Fill push
+	if config, err = fillPush(config, c); err != nil {
+		return releaseman.Config{}, err
+	}
+	if config.Release.Push {
+		if err := ensureNotBehindRemote(config); err != nil {
+			return releaseman.Config{}, err
+		}
+	}
+
 	return config, nil
 }
 
-func generateRelease(config releaseman.Config) {
+func signingOptions(config releaseman.Config) *git.SigningOptions {
+	return &git.SigningOptions{
+		Format: config.Release.Signing.Format,
+		Key:    config.Release.Signing.Key,
+	}
+}
+
+func releaseCommitOptions(config releaseman.Config) git.CommitOptions {
+	if !config.Release.SignCommits {
+		return git.CommitOptions{}
+	}
+	return git.CommitOptions{Sign: signingOptions(config)}
+}
+
+// releaseTagOptions returns the options of the release tag,
+// the message of an annotated tag is rendered from the commits since the last release.
+func releaseTagOptions(config releaseman.Config) (git.TagOptions, error) {
+	if !config.Release.Tag.Annotated && !config.Release.Tag.Sign {
+		return git.TagOptions{}, nil
+	}
+
+	scheme, err := config.VersionScheme()
+	if err != nil {
+		return git.TagOptions{}, err
+	}
+
+	taggedCommits, err := git.BranchVersionTaggedCommits(config.Release.TagFormat, scheme, config.Release.DevelopmentBranch, config.Release.ReleaseBranch)
+	if err != nil {
+		return git.TagOptions{}, fmt.Errorf("Failed to get tagged commits, error: %s", err)
+	}
+	var lastTaggedCommitPtr *git.CommitModel
+	if len(taggedCommits) > 0 {
+		lastTaggedCommitPtr = &(taggedCommits[len(taggedCommits)-1])
+	}
+
+	commits, err := git.GetCommitsFrom(lastTaggedCommitPtr)
+	if err != nil {
+		return git.TagOptions{}, fmt.Errorf("Failed to get commits, error: %s", err)
+	}
+
+	releaseNotes := releaseman.NewReleaseNotes(commits, lastTaggedCommitPtr, config)
+	message, err := releaseman.RenderReleaseNotes(releaseNotes, config.Release.Tag.MessageTemplate)
+	if err != nil {
+		return git.TagOptions{}, err
+	}
+
+	options := git.TagOptions{Message: message}
+	if config.Release.Tag.Sign {
+		options.Sign = signingOptions(config)
+	}
+	return options, options.Validate()
+}
+
+func generateRelease(config releaseman.Config) error {
+	commitOptions := releaseCommitOptions(config)
+	if commitOptions.Sign != nil {
+		if err := commitOptions.Sign.Validate(); err != nil {
+			return err
+		}
+	}
+	tagOptions, err := releaseTagOptions(config)
+	if err != nil {
+		return err
+	}
+
 	fmt.Println()
 	log.Infof("=> Adding changes to git...")
 	changes, err := git.GetChangedFiles()
 	if err != nil {
-		log.Fatalf("Failed to get changes, error: %s", err)
+		return fmt.Errorf("Failed to get changes, error: %s", err)
 	}
 	if err := git.Add(changes); err != nil {
-		log.Fatalf("Failed to git add, error: %s", err)
+		return fmt.Errorf("Failed to git add, error: %s", err)
 	}
-	if err := git.Commit(fmt.Sprintf("v%s", config.Release.Version)); err != nil {
-		log.Fatalf("Failed to git commit, error: %s", err)
+	if err := git.Commit(fmt.Sprintf("v%s", config.Release.Version), commitOptions); err != nil {
+		return fmt.Errorf("Failed to git commit, error: %s", err)
+	}
+
+	if config.Component != nil {
+		// merging the development branch would release the unreleased changes of the other components as well,
+		// so the release commit of the component is tagged on the development branch
+		fmt.Println()
+		log.Infof("=> Tagging the release commit of the component...")
+		if err := git.Tag(config.ReleaseTag(), tagOptions); err != nil {
+			return fmt.Errorf("Failed to git tag, error: %s", err)
+		}
+		return nil
 	}
 
 	fmt.Println()
 	log.Infof("=> Merging changes into release branch...")
 	if err := git.CheckoutBranch(config.Release.ReleaseBranch); err != nil {
-		log.Fatalf("Failed to git checkout, error: %s", err)
+		return fmt.Errorf("Failed to git checkout, error: %s", err)
 	}
 	mergeCommitMessage := fmt.Sprintf("Merge %s into %s, release: v%s", config.Release.DevelopmentBranch, config.Release.ReleaseBranch, config.Release.Version)
-	if err := git.Merge(config.Release.DevelopmentBranch, mergeCommitMessage); err != nil {
-		log.Fatalf("Failed to git merge, error: %s", err)
+	if err := git.Merge(config.Release.DevelopmentBranch, mergeCommitMessage, commitOptions); err != nil {
+		return fmt.Errorf("Failed to git merge, error: %s", err)
 	}
 
 	fmt.Println()
 	log.Infof("=> Tagging release branch...")
-	if err := git.Tag(config.Release.Version); err != nil {
-		log.Fatalf("Failed to git tag, error: %s", err)
+	if err := git.Tag(config.ReleaseTag(), tagOptions); err != nil {
+		return fmt.Errorf("Failed to git tag, error: %s", err)
 	}
 	if err := git.CheckoutBranch(config.Release.DevelopmentBranch); err != nil {
-		log.Fatalf("Failed to git checkout, error: %s", err)
+		return fmt.Errorf("Failed to git checkout, error: %s", err)
+	}
+
+	return nil
+}
+
+// pushRelease pushes the release and development branches and the release tag to the remote, atomically.
+// A component release does not change the release branch, it is not pushed.
+func pushRelease(config releaseman.Config) error {
+	fmt.Println()
+	log.Infof("=> Pushing release to remote (%s)...", config.Release.Remote)
+	branches := []string{config.Release.DevelopmentBranch}
+	if config.Component == nil {
+		branches = append(branches, config.Release.ReleaseBranch)
+	}
+	if err := git.Push(config.Release.Remote, branches, []string{config.ReleaseTag()}); err != nil {
+		return fmt.Errorf("Failed to git push, error: %s", err)
+	}
+	return nil
+}
+
+// releaseState is the state of the repository before the release, used to roll back a failed release.
+type releaseState struct {
+	git              git.RepositoryState
+	changelogPath    string
+	changelogExisted bool
+	changelog        string
+}
+
+func captureReleaseState(config releaseman.Config, withChangelog bool) (releaseState, error) {
+	gitState, err := git.CaptureState(config.Release.DevelopmentBranch, config.Release.ReleaseBranch)
+	if err != nil {
+		return releaseState{}, err
+	}
+
+	state := releaseState{git: gitState}
+	if withChangelog && config.Changelog.Path != "" {
+		state.changelogPath = config.Changelog.Path
+		if state.changelogExisted, err = pathutil.IsPathExists(config.Changelog.Path); err != nil {
+			return releaseState{}, err
+		}
+		if state.changelogExisted {
+			if state.changelog, err = fileutil.ReadStringFromFile(config.Changelog.Path); err != nil {
+				return releaseState{}, err
+			}
+		}
+	}
+
+	return state, nil
+}
+
+// restoreChangelog writes back the content of the changelog from before the release (or removes the changelog,
+// if it did not exist), as the changelog is not restored by the git reset if it is not committed.
+// It returns the undone change, empty if the changelog was not changed.
+func restoreChangelog(pth string, existed bool, content string) (string, error) {
+	exist, err := pathutil.IsPathExists(pth)
+	if err != nil {
+		return "", err
+	}
+
+	if !existed {
+		if !exist {
+			return "", nil
+		}
+		if err := os.Remove(pth); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("removed changelog %s", pth), nil
+	}
+
+	if exist {
+		current, err := fileutil.ReadStringFromFile(pth)
+		if err != nil {
+			return "", err
+		}
+		if current == content {
+			return "", nil
+		}
+	}
+	if err := fileutil.WriteStringToFile(pth, content); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("restored changelog %s", pth), nil
+}
+
+// rollbackRelease restores the repository to the state captured before the release, removes the files
+// created by the release (e.g. a new changelog, the changelog data and the store notes) and reports what was undone.
+func rollbackRelease(state releaseState) {
+	fmt.Println()
+	log.Warnf("=> Rolling back the release...")
+
+	// the files are restored even if the git state could not be, the failure is reported at the end
+	undone, restoreErr := git.RestoreState(state.git)
+	for _, change := range undone {
+		log.Warnf("* %s", change)
+	}
+	if restoreErr != nil {
+		log.Errorf("Failed to restore the git state, error: %s", restoreErr)
+	}
+
+	if state.changelogPath != "" {
+		if change, err := restoreChangelog(state.changelogPath, state.changelogExisted, state.changelog); err != nil {
+			log.Errorf("Failed to restore the changelog (%s), error: %s", state.changelogPath, err)
+		} else if change != "" {
+			undone = append(undone, change)
+			log.Warnf("* %s", change)
+		}
+	}
+
+	// the reset restores the changed files, the created (untracked) ones have to be removed
+	for _, pth := range releaseman.CreatedFiles() {
+		if exist, err := pathutil.IsPathExists(pth); err != nil {
+			log.Errorf("Failed to check if path exist, error: %s", err)
+		} else if exist {
+			if err := os.Remove(pth); err != nil {
+				log.Errorf("Failed to remove file (%s), error: %s", pth, err)
+			} else {
+				undone = append(undone, fmt.Sprintf("removed %s", pth))
+				log.Warnf("* removed %s", pth)
+			}
+		}
+	}
+
+	if restoreErr != nil {
+		log.Errorf("The release is rolled back only partially, restore the rest manually:")
+		log.Errorf("Current branch was: %s", state.git.CurrentBranch)
+		for branch, head := range state.git.BranchHeads {
+			log.Errorf("Branch (%s) head was: %s", branch, head)
+		}
+		for _, pth := range releaseman.CreatedFiles() {
+			if exist, err := pathutil.IsPathExists(pth); err == nil && exist {
+				log.Errorf("File (%s) was created by the release", pth)
+			}
+		}
+		return
+	}
+
+	if len(undone) == 0 {
+		log.Warnf("Nothing to roll back")
 	}
 }
 
@@ -88,7 +324,7 @@ func createRelease(c *cli.Context) {
 	//
 	// Fail if git is not clean
 	if err := ensureCleanGit(); err != nil {
-		log.Fatalf("Ensure clean git failed, error: %#v", err)
+		log.Fatalf("Ensure clean git failed, error: %s", err)
 	}
 
 	//
@@ -112,16 +348,14 @@ func createRelease(c *cli.Context) {
 
 	config, err := collectReleaseConfigParams(config, c)
 	if err != nil {
-		log.Fatalf("Failed to collect config params, error: %#v", err)
+		log.Fatalf("Failed to collect config params, error: %s", err)
 	}
 
-	printRollBackMessage()
-
 	//
 	// Validate config
 	config.Print(releaseman.ReleaseMode)
 
-	if !releaseman.IsCIMode {
+	if !releaseman.IsCIMode && !releaseman.IsDryRun {
 		ok, err := goinp.AskForBoolWithDefault("Are you ready for release?", true)
 		if err != nil {
 			log.Fatalf("Failed to ask for input, error: %s", err)
@@ -132,19 +366,52 @@ func createRelease(c *cli.Context) {
 	}
 
 	//
-	// Run set version script
-	if c.IsSet(SetVersionScriptKey) {
-		setVersionScript := c.String(SetVersionScriptKey)
-		if err := runSetVersionScript(setVersionScript, config.Release.Version); err != nil {
-			log.Fatalf("Failed to run set version script, error: %#v", err)
-		}
+	// Save the state to roll back to
+	state, err := captureReleaseState(config, false)
+	if err != nil {
+		log.Fatalf("Failed to save the repository state, error: %s", err)
+	}
+
+	//
+	// Set version
+	if err := setVersion(config, c); err != nil {
+		rollbackRelease(state)
+		log.Fatalf("Failed to set version, error: %s", err)
 	}
 
 	//
 	// Create release git changes
-	generateRelease(config)
+	if err := generateRelease(config); err != nil {
+		rollbackRelease(state)
+		log.Fatalf("Failed to create release, error: %s", err)
+	}
+
+	//
+	// Push the release
+	if config.Release.Push {
+		if err := pushRelease(config); err != nil {
+			rollbackRelease(state)
+			log.Fatalf("Failed to push release, error: %s", err)
+		}
+	}
+
+	if releaseman.IsDryRun {
+		printDryRunSummary(config)
+		return
+	}
+
+	if config.Release.Push {
+		fmt.Println()
+		log.Infoln(colorstring.Greenf("v%s released and pushed to %s 🚀", config.Release.Version, config.Release.Remote))
+		return
+	}
+
+	if err := recordRelease(config, state); err != nil {
+		log.Warnf("Failed to record the release, it can not be rolled back by releaseman, error: %s", err)
+	}
 
 	fmt.Println()
 	log.Infoln(colorstring.Greenf("v%s released 🚀", config.Release.Version))
 	log.Infoln("Take a look at your git, and if you are happy with the release, push the changes.")
+	log.Infoln("To undo the release call: releaseman rollback")
 }
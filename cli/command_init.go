@@ -71,7 +71,7 @@ func initRelease(c *cli.Context) {
 
 	releaseConfig, err := collectInitConfigParams(releaseman.Config{}, c)
 	if err != nil {
-		log.Fatalf("Failed to collect config params, error: %#v", err)
+		log.Fatalf("Failed to collect config params, error: %s", err)
 	}
 	releaseConfig.Changelog.ContentTemplate = releaseman.ChangelogContentTemplate
 	releaseConfig.Changelog.HeaderTemplate = releaseman.ChangelogHeaderTemplate
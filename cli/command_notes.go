@@ -0,0 +1,198 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/bitrise-tools/releaseman/git"
+	"github.com/bitrise-tools/releaseman/releaseman"
+	"github.com/bitrise-tools/releaseman/versioning"
+	"github.com/codegangsta/cli"
+)
+
+//=======================================
+// Utility
+//=======================================
+
+// notesRange returns the commit range of the notes: the start and the end state, if they are set,
+// otherwise the tag of the version and the previous version tag reachable from it.
+func notesRange(config releaseman.Config, scheme versioning.Scheme, version, startState, endState string) (*git.CommitModel, git.CommitModel, error) {
+	taggedCommits, err := git.VersionTaggedCommits(config.Release.TagFormat, scheme)
+	if err != nil {
+		return nil, git.CommitModel{}, fmt.Errorf("Failed to get tagged commits, error: %s", err)
+	}
+
+	endCommit := git.CommitModel{}
+	if version != "" && endState == "" && startState == "" {
+		tag := config.Release.TagFormat.Tag(version)
+		for _, taggedCommit := range taggedCommits {
+			if taggedCommit.Tag == tag {
+				endCommit = taggedCommit
+			}
+		}
+		if endCommit.Hash == "" {
+			return nil, git.CommitModel{}, fmt.Errorf("Version (%s) is not tagged (%s)", version, tag)
+		}
+	} else {
+		if endState == "" {
+			endState = "HEAD"
+		}
+		if endCommit, err = git.CommitOf(endState); err != nil {
+			return nil, git.CommitModel{}, fmt.Errorf("Failed to find the end state (%s), error: %s", endState, err)
+		}
+
+		// the version of the end state is its highest version tag
+		endCommit.Tag = endState
+		for _, taggedCommit := range taggedCommits {
+			if taggedCommit.Hash == endCommit.Hash {
+				endCommit.Tag = taggedCommit.Tag
+				endCommit.Version = taggedCommit.Version
+			}
+		}
+	}
+
+	if startState != "" {
+		startCommit, err := git.CommitOf(startState)
+		if err != nil {
+			return nil, git.CommitModel{}, fmt.Errorf("Failed to find the start state (%s), error: %s", startState, err)
+		}
+		startCommit.Tag = startState
+		return &startCommit, endCommit, nil
+	}
+
+	// the previous version is the highest lower version tag reachable from the end commit
+	if version == "" {
+		version = endCommit.Version
+	}
+	for idx := len(taggedCommits) - 1; idx >= 0; idx-- {
+		taggedCommit := taggedCommits[idx]
+		if taggedCommit.Hash == endCommit.Hash {
+			continue
+		}
+		if version != "" {
+			if compare, err := scheme.Compare(taggedCommit.Version, version); err != nil || compare >= 0 {
+				continue
+			}
+		}
+
+		if reachable, err := git.IsAncestor(taggedCommit.Hash, endCommit.Hash); err != nil {
+			return nil, git.CommitModel{}, err
+		} else if reachable {
+			return &taggedCommit, endCommit, nil
+		}
+	}
+
+	return nil, endCommit, nil
+}
+
+func versionNotes(config releaseman.Config, c *cli.Context) (string, error) {
+	version := c.Args().First()
+	startState := c.String(StartStateKey)
+	endState := c.String(EndStateKey)
+
+	switch {
+	case version != "" && c.Bool(LatestKey):
+		return "", errors.New("Either the version or --latest can be specified")
+	case (startState != "" || endState != "") && (c.Bool(LatestKey) || c.Bool(FromChangelogKey)):
+		return "", errors.New("The start and end states can not be combined with --latest and --from-changelog")
+	case version == "" && !c.Bool(LatestKey) && startState == "" && endState == "":
+		return "", errors.New("Missing required input: version")
+	}
+
+	scheme, err := config.VersionScheme()
+	if err != nil {
+		return "", err
+	}
+
+	if c.Bool(LatestKey) {
+		// the latest version is the highest version tag, whichever branch it is on
+		taggedCommits, err := git.VersionTaggedCommits(config.Release.TagFormat, scheme)
+		if err != nil {
+			return "", fmt.Errorf("Failed to get tagged commits, error: %s", err)
+		}
+		if len(taggedCommits) == 0 {
+			return "", errors.New("No version tag found")
+		}
+		version = taggedCommits[len(taggedCommits)-1].Version
+	}
+
+	if c.Bool(FromChangelogKey) {
+		if config.Changelog.Path == "" {
+			return "", errors.New("Missing required input: changelog path")
+		}
+		changelog, err := fileutil.ReadStringFromFile(config.Changelog.Path)
+		if err != nil {
+			return "", err
+		}
+		return releaseman.ExtractVersionNotes(changelog, version)
+	}
+
+	startCommitPtr, endCommit, err := notesRange(config, scheme, version, startState, endState)
+	if err != nil {
+		return "", err
+	}
+
+	commits, err := git.GetCommitsBetween(startCommitPtr, endCommit)
+	if err != nil {
+		return "", fmt.Errorf("Failed to get commits, error: %s", err)
+	}
+
+	changelog := releaseman.NewVersionChangelog(commits, startCommitPtr, endCommit, version, config)
+	return releaseman.RenderVersionNotes(changelog, config)
+}
+
+//=======================================
+// Main
+//=======================================
+
+func notes(c *cli.Context) {
+	//
+	// Build config
+	config := releaseman.Config{}
+	configPath := ""
+	if c.IsSet("config") {
+		configPath = c.String("config")
+	} else {
+		configPath = releaseman.DefaultConfigPth
+	}
+
+	if exist, err := pathutil.IsPathExists(configPath); err != nil {
+		log.Warnf("Failed to check if path exist, error: %#v", err)
+	} else if exist {
+		config, err = releaseman.NewConfigFromFile(configPath)
+		if err != nil {
+			log.Fatalf("Failed to parse release config at (%s), error: %#v", configPath, err)
+		}
+	}
+
+	config, err := fillComponent(config, c)
+	if err != nil {
+		log.Fatalf("Failed to collect config params, error: %s", err)
+	}
+	config = fillChangelogPathFilter(config, c)
+	if c.IsSet(ChangelogPathKey) {
+		config.Changelog.Path = c.String(ChangelogPathKey)
+	}
+
+	//
+	// Render notes
+	notes, err := versionNotes(config, c)
+	if err != nil {
+		log.Fatalf("Failed to create release notes, error: %s", err)
+	}
+
+	if output := c.String(OutputKey); output != "" {
+		if err := releaseman.WriteFile(output, notes+"\n"); err != nil {
+			log.Fatalf("Failed to write release notes, error: %s", err)
+		}
+		if !releaseman.IsDryRun {
+			log.Infof("Release notes written (%s)", output)
+		}
+		return
+	}
+
+	fmt.Println(notes)
+}
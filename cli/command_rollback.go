@@ -0,0 +1,212 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/bitrise-io/go-utils/colorstring"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/bitrise-io/goinp/goinp"
+	"github.com/bitrise-tools/releaseman/git"
+	"github.com/bitrise-tools/releaseman/releaseman"
+	"github.com/codegangsta/cli"
+)
+
+//=======================================
+// Utility
+//=======================================
+
+// ensureReleaseNotChanged fails if the branches or the tag of the release were modified since the release.
+func ensureReleaseNotChanged(record releaseman.ReleaseRecord) error {
+	for branch, head := range record.After.BranchHeads {
+		currentHead, err := git.BranchHead(branch)
+		if err != nil {
+			return err
+		}
+		if currentHead != head {
+			return fmt.Errorf("Branch (%s) has changed since the release (head was: %s, now: %s)", branch, head, currentHead)
+		}
+	}
+
+	taggedCommit, err := git.CommitOfTag(record.Tag)
+	if err != nil {
+		return fmt.Errorf("Failed to find the release tag (%s), error: %s", record.Tag, err)
+	}
+	if taggedCommit.Hash != record.TagCommit {
+		return fmt.Errorf("Tag (%s) has changed since the release (commit was: %s, now: %s)", record.Tag, record.TagCommit, taggedCommit.Hash)
+	}
+
+	return nil
+}
+
+// ensureReleaseNotPushed fails if a commit or the tag of the release can be found on any remote.
+func ensureReleaseNotPushed(record releaseman.ReleaseRecord) error {
+	for branch, head := range record.After.BranchHeads {
+		if head == record.Before.BranchHeads[branch] {
+			continue
+		}
+
+		remoteBranches, err := git.RemoteBranchesContaining(head)
+		if err != nil {
+			return err
+		}
+		if len(remoteBranches) > 0 {
+			return fmt.Errorf("The release commit of branch (%s) was already pushed to: %s", branch, strings.Join(remoteBranches, ", "))
+		}
+	}
+
+	remotes, err := git.Remotes()
+	if err != nil {
+		return err
+	}
+	for _, remote := range remotes {
+		tags, err := git.RemoteTags(remote)
+		if err != nil {
+			return fmt.Errorf("Failed to list the tags of remote (%s), can not verify that the release was not pushed, error: %s", remote, err)
+		}
+		for _, tag := range tags {
+			if tag == record.Tag {
+				return fmt.Errorf("The release tag (%s) was already pushed to remote (%s)", record.Tag, remote)
+			}
+		}
+	}
+
+	return nil
+}
+
+// recordRelease saves the states around the release, to make the release revertible by the rollback command.
+func recordRelease(config releaseman.Config, state releaseState) error {
+	gitDir, err := git.GitDir()
+	if err != nil {
+		return err
+	}
+
+	after, err := git.CaptureState(config.Release.DevelopmentBranch, config.Release.ReleaseBranch)
+	if err != nil {
+		return err
+	}
+
+	taggedCommit, err := git.CommitOfTag(config.ReleaseTag())
+	if err != nil {
+		return err
+	}
+
+	record := releaseman.ReleaseRecord{
+		Version:          config.Release.Version,
+		Tag:              config.ReleaseTag(),
+		TagCommit:        taggedCommit.Hash,
+		ChangelogPath:    state.changelogPath,
+		ChangelogExisted: state.changelogExisted,
+		Changelog:        state.changelog,
+		Before:           state.git,
+		After:            after,
+	}
+	return releaseman.WriteReleaseRecord(releaseman.ReleaseRecordPath(gitDir), record)
+}
+
+//=======================================
+// Main
+//=======================================
+
+func rollback(c *cli.Context) {
+	//
+	// Fail if git is not clean
+	if err := ensureCleanGit(); err != nil {
+		log.Fatalf("Ensure clean git failed, error: %s", err)
+	}
+
+	//
+	// Read the last release
+	gitDir, err := git.GitDir()
+	if err != nil {
+		log.Fatalf("Failed to find the .git directory, error: %s", err)
+	}
+	recordPath := releaseman.ReleaseRecordPath(gitDir)
+
+	if exist, err := pathutil.IsPathExists(recordPath); err != nil {
+		log.Fatalf("Failed to check if path exist, error: %s", err)
+	} else if !exist {
+		log.Fatal("No local release found to roll back")
+	}
+
+	record, err := releaseman.ReadReleaseRecord(recordPath)
+	if err != nil {
+		log.Fatalf("Failed to read the last release (%s), error: %s", recordPath, err)
+	}
+
+	//
+	// Verify the release
+	if err := ensureReleaseNotChanged(record); err != nil {
+		log.Fatalf("Can not roll back v%s: %s", record.Version, err)
+	}
+	if err := ensureReleaseNotPushed(record); err != nil {
+		log.Fatalf("Can not roll back v%s: %s", record.Version, err)
+	}
+
+	fmt.Println()
+	log.Infof("Rolling back v%s:", record.Version)
+	log.Infof(" * delete tag: %s", record.Tag)
+	for branch, head := range record.Before.BranchHeads {
+		if head != record.After.BranchHeads[branch] {
+			log.Infof(" * reset branch %s to: %s", branch, head)
+		}
+	}
+	if record.ChangelogPath != "" {
+		log.Infof(" * restore changelog: %s", record.ChangelogPath)
+	}
+
+	if !releaseman.IsCIMode && !releaseman.IsDryRun {
+		ok, err := goinp.AskForBoolWithDefault("Are you sure you want to roll back the release?", true)
+		if err != nil {
+			log.Fatalf("Failed to ask for input, error: %s", err)
+		}
+		if !ok {
+			log.Fatal("Aborted rollback")
+		}
+	}
+
+	//
+	// Roll back, only the release tag is deleted
+	state := record.Before
+	if state.Tags, err = git.Tags(); err != nil {
+		log.Fatalf("Failed to list tags, error: %s", err)
+	}
+	for idx, tag := range state.Tags {
+		if tag == record.Tag {
+			state.Tags = append(state.Tags[:idx], state.Tags[idx+1:]...)
+			break
+		}
+	}
+
+	fmt.Println()
+	undone, err := git.RestoreState(state)
+	for _, change := range undone {
+		log.Infof("* %s", change)
+	}
+	if err != nil {
+		log.Fatalf("Failed to roll back the release, error: %s", err)
+	}
+
+	if record.ChangelogPath != "" {
+		if releaseman.IsDryRun {
+			log.Infof("[dry-run] restore changelog %s", record.ChangelogPath)
+		} else if change, err := restoreChangelog(record.ChangelogPath, record.ChangelogExisted, record.Changelog); err != nil {
+			log.Fatalf("Failed to restore the changelog (%s), error: %s", record.ChangelogPath, err)
+		} else if change != "" {
+			log.Infof("* %s", change)
+		}
+	}
+
+	if releaseman.IsDryRun {
+		printDryRunSummary(releaseman.Config{Release: releaseman.Release{Version: record.Version}})
+		return
+	}
+
+	if err := releaseman.RemoveReleaseRecord(recordPath); err != nil {
+		log.Warnf("Failed to remove the release record (%s), error: %s", recordPath, err)
+	}
+
+	fmt.Println()
+	log.Infoln(colorstring.Greenf("v%s rolled back", record.Version))
+}
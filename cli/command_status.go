@@ -0,0 +1,233 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/bitrise-tools/releaseman/git"
+	"github.com/bitrise-tools/releaseman/releaseman"
+	"github.com/bitrise-tools/releaseman/versioning"
+	"github.com/codegangsta/cli"
+)
+
+//=======================================
+// Consts
+//=======================================
+
+const (
+	// TextFormat ...
+	TextFormat = "text"
+	// JSONFormat ...
+	JSONFormat = "json"
+)
+
+// statusBumpKinds are the bump kinds listed by the status command, in order.
+var statusBumpKinds = []string{PatchKey, MinorKey, MajorKey, PrepatchKey, PreminorKey, PremajorKey, PrereleaseKey, ReleaseKey}
+
+//=======================================
+// Models
+//=======================================
+
+// StatusModel is the release status of the development branch.
+type StatusModel struct {
+	DevelopmentBranch string `json:"development_branch"`
+	ReleaseBranch     string `json:"release_branch,omitempty"`
+	// CurrentVersion is the output of the get version script, the version of the version files, or the last tagged version
+	CurrentVersion string `json:"current_version"`
+	// LastTag is the last version tag reachable from the development or the release branch
+	LastTag string `json:"last_tag,omitempty"`
+	// UnreleasedCommits is the number of commits since the last tag, the changelog path filters are applied
+	UnreleasedCommits int `json:"unreleased_commits"`
+	// NextVersions are the versions of the bump kinds, the kinds not supported by the version scheme are left out
+	NextVersions map[string]string `json:"next_versions"`
+	// AutoBump is the bump kind picked by --bump-version auto, empty if no unreleased commit is releasable
+	AutoBump string `json:"auto_bump,omitempty"`
+	// ReleaseBranchBehind is true if the release branch does not contain the development branch,
+	// nil if the release branch is unknown
+	ReleaseBranchBehind *bool `json:"release_branch_behind,omitempty"`
+	Clean               bool  `json:"clean"`
+}
+
+//=======================================
+// Utility
+//=======================================
+
+func collectStatus(config releaseman.Config, c *cli.Context) (StatusModel, error) {
+	scheme, err := config.VersionScheme()
+	if err != nil {
+		return StatusModel{}, err
+	}
+
+	status := StatusModel{
+		DevelopmentBranch: config.Release.DevelopmentBranch,
+		ReleaseBranch:     config.Release.ReleaseBranch,
+		NextVersions:      map[string]string{},
+	}
+
+	uncommitedChanges, err := git.AreUncommitedChanges()
+	if err != nil {
+		return StatusModel{}, err
+	}
+	status.Clean = !uncommitedChanges
+
+	// the unreleased commits are read from the development branch, whichever branch is checked out
+	developmentHead, err := git.BranchHead(config.Release.DevelopmentBranch)
+	if err != nil {
+		return StatusModel{}, fmt.Errorf("Failed to find the development branch (%s), error: %s", config.Release.DevelopmentBranch, err)
+	}
+
+	tags, err := git.BranchVersionTaggedCommits(config.Release.TagFormat, scheme, config.Release.DevelopmentBranch, config.Release.ReleaseBranch)
+	if err != nil {
+		return StatusModel{}, fmt.Errorf("Failed to get tagged commits, error: %s", err)
+	}
+	var lastTaggedCommitPtr *git.CommitModel
+	if len(tags) > 0 {
+		lastTaggedCommitPtr = &(tags[len(tags)-1])
+		status.LastTag = lastTaggedCommitPtr.Tag
+	}
+
+	commits, err := git.GetCommitsBetween(lastTaggedCommitPtr, git.CommitModel{Hash: developmentHead})
+	if err != nil {
+		return StatusModel{}, fmt.Errorf("Failed to get commits, error: %s", err)
+	}
+	commits = config.FilterCommits(commits)
+	status.UnreleasedCommits = len(commits)
+
+	if status.CurrentVersion, err = readCurrentVersion(config, c, tags); err != nil {
+		return StatusModel{}, err
+	}
+	if status.CurrentVersion != "" {
+		bumpOptions := versioning.BumpOptions{PrereleaseIdentifier: config.Release.PrereleaseIdentifier}
+		for _, kind := range statusBumpKinds {
+			if version, err := scheme.Bump(status.CurrentVersion, kind, bumpOptions); err == nil {
+				status.NextVersions[kind] = version
+			}
+		}
+		status.AutoBump, _ = autoBumpSegment(commits)
+	}
+
+	if config.Release.ReleaseBranch != "" {
+		releaseHead, err := git.BranchHead(config.Release.ReleaseBranch)
+		if err != nil {
+			return StatusModel{}, fmt.Errorf("Failed to find the release branch (%s), error: %s", config.Release.ReleaseBranch, err)
+		}
+		contains, err := git.IsAncestor(developmentHead, releaseHead)
+		if err != nil {
+			return StatusModel{}, err
+		}
+		behind := !contains
+		status.ReleaseBranchBehind = &behind
+	}
+
+	return status, nil
+}
+
+func printStatus(status StatusModel) {
+	fmt.Printf("Development branch: %s\n", status.DevelopmentBranch)
+	if status.CurrentVersion == "" {
+		fmt.Println("Current version: -")
+	} else {
+		fmt.Printf("Current version: %s\n", status.CurrentVersion)
+	}
+	if status.LastTag != "" {
+		fmt.Printf("Last tag: %s\n", status.LastTag)
+	}
+	fmt.Printf("Unreleased commits: %d\n", status.UnreleasedCommits)
+
+	if len(status.NextVersions) > 0 {
+		fmt.Println("Next versions:")
+		for _, kind := range statusBumpKinds {
+			if version, ok := status.NextVersions[kind]; ok {
+				fmt.Printf(" * %s: %s\n", kind, version)
+			}
+		}
+		if status.AutoBump != "" {
+			fmt.Printf(" * %s: %s (%s)\n", AutoKey, status.NextVersions[status.AutoBump], status.AutoBump)
+		}
+	}
+
+	if status.ReleaseBranchBehind != nil {
+		if *status.ReleaseBranchBehind {
+			fmt.Printf("Release branch (%s): behind the development branch\n", status.ReleaseBranch)
+		} else {
+			fmt.Printf("Release branch (%s): up to date\n", status.ReleaseBranch)
+		}
+	}
+
+	if status.Clean {
+		fmt.Println("Working tree: clean")
+	} else {
+		fmt.Println("Working tree: uncommited changes")
+	}
+}
+
+//=======================================
+// Main
+//=======================================
+
+func status(c *cli.Context) {
+	format := c.String(FormatKey)
+	if format != TextFormat && format != JSONFormat {
+		log.Fatalf("Invalid format (%s), options: %s, %s", format, TextFormat, JSONFormat)
+	}
+
+	//
+	// Build config
+	config := releaseman.Config{}
+	configPath := ""
+	if c.IsSet("config") {
+		configPath = c.String("config")
+	} else {
+		configPath = releaseman.DefaultConfigPth
+	}
+
+	if exist, err := pathutil.IsPathExists(configPath); err != nil {
+		log.Warnf("Failed to check if path exist, error: %#v", err)
+	} else if exist {
+		config, err = releaseman.NewConfigFromFile(configPath)
+		if err != nil {
+			log.Fatalf("Failed to parse release config at (%s), error: %#v", configPath, err)
+		}
+	}
+
+	config, err := fillComponent(config, c)
+	if err != nil {
+		log.Fatalf("Failed to collect config params, error: %s", err)
+	}
+	config = fillChangelogPathFilter(config, c)
+	if c.IsSet(DevelopmentBranchKey) {
+		config.Release.DevelopmentBranch = c.String(DevelopmentBranchKey)
+	}
+	if c.IsSet(ReleaseBranchKey) {
+		config.Release.ReleaseBranch = c.String(ReleaseBranchKey)
+	}
+	if c.IsSet(PrereleaseIdentifierKey) {
+		config.Release.PrereleaseIdentifier = c.String(PrereleaseIdentifierKey)
+	}
+	if config.Release.DevelopmentBranch == "" {
+		// the development branch defaults to the current branch
+		if config.Release.DevelopmentBranch, err = git.CurrentBranchName(); err != nil {
+			log.Fatalf("Failed to get the current branch, error: %s", err)
+		}
+	}
+
+	//
+	// Collect status
+	releaseStatus, err := collectStatus(config, c)
+	if err != nil {
+		log.Fatalf("Failed to collect the release status, error: %s", err)
+	}
+
+	if format == JSONFormat {
+		bytes, err := json.MarshalIndent(releaseStatus, "", "  ")
+		if err != nil {
+			log.Fatalf("Failed to marshal the release status, error: %s", err)
+		}
+		fmt.Println(string(bytes))
+		return
+	}
+
+	printStatus(releaseStatus)
+}
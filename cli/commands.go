@@ -1,6 +1,9 @@
 package cli
 
-import "github.com/codegangsta/cli"
+import (
+	"github.com/bitrise-tools/releaseman/versioning"
+	"github.com/codegangsta/cli"
+)
 
 const (
 	// LogLevelEnvKey ...
@@ -22,6 +25,20 @@ const (
 	// CIModeEnvKey ...
 	CIModeEnvKey = "CI"
 
+	// DryRunKey ...
+	DryRunKey = "dry-run"
+	// DryRunEnvKey ...
+	DryRunEnvKey = "RELEASEMAN_DRY_RUN"
+
+	// GitBackendKey ...
+	GitBackendKey = "git-backend"
+	// GitBackendEnvKey ...
+	GitBackendEnvKey = "RELEASEMAN_GIT_BACKEND"
+	// ExecGitBackend ...
+	ExecGitBackend = "exec"
+	// GoGitBackend ...
+	GoGitBackend = "go-git"
+
 	// DevelopmentBranchKey ...
 	DevelopmentBranchKey = "development-branch"
 
@@ -34,17 +51,59 @@ const (
 	// EndStateKey ...
 	EndStateKey = "end-state"
 
+	// LatestKey ...
+	LatestKey = "latest"
+	// FromChangelogKey ...
+	FromChangelogKey = "from-changelog"
+	// OutputKey ...
+	OutputKey      = "output"
+	outputKeyShort = "o"
+
+	// FormatKey ...
+	FormatKey = "format"
+
 	// ChangelogPathKey ...
 	ChangelogPathKey = "changelog-path"
+	// UnreleasedKey ...
+	UnreleasedKey = "unreleased"
+	// IncludePathKey ...
+	IncludePathKey = "include-path"
+	// ExcludePathKey ...
+	ExcludePathKey = "exclude-path"
 
 	// BumpVersionKey ...
 	BumpVersionKey = "bump-version"
 	// PatchKey ...
-	PatchKey = "patch"
+	PatchKey = versioning.PatchBump
 	// MinorKey ...
-	MinorKey = "minor"
+	MinorKey = versioning.MinorBump
 	// MajorKey ...
-	MajorKey = "major"
+	MajorKey = versioning.MajorBump
+	// AutoKey ...
+	AutoKey = "auto"
+	// PrereleaseKey ...
+	PrereleaseKey = versioning.PrereleaseBump
+	// PremajorKey ...
+	PremajorKey = versioning.PremajorBump
+	// PreminorKey ...
+	PreminorKey = versioning.PreminorBump
+	// PrepatchKey ...
+	PrepatchKey = versioning.PrepatchBump
+	// ReleaseKey ...
+	ReleaseKey = versioning.ReleaseBump
+
+	// PrereleaseIdentifierKey ...
+	PrereleaseIdentifierKey = "preid"
+	// BuildMetadataKey ...
+	BuildMetadataKey = "build-metadata"
+
+	// PushKey ...
+	PushKey = "push"
+	// RemoteKey ...
+	RemoteKey = "remote"
+
+	// ComponentKey ...
+	ComponentKey = "component"
 
 	// GetVersionScriptKey ...
 	GetVersionScriptKey = "get-version-script"
@@ -60,6 +119,10 @@ var (
 			Usage:  "Create changelog and release new version",
 			Action: create,
 			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  ComponentKey,
+					Usage: "Component to release (monorepo)",
+				},
 				cli.StringFlag{
 					Name:  DevelopmentBranchKey,
 					Usage: "Development branch",
@@ -75,7 +138,15 @@ var (
 				cli.StringFlag{
 					Name:  BumpVersionKey,
 					Value: "patch",
-					Usage: "Bump version (options: patch, minor, major).",
+					Usage: "Bump version (options: patch, minor, major, auto, prerelease, premajor, preminor, prepatch, release).",
+				},
+				cli.StringFlag{
+					Name:  PrereleaseIdentifierKey,
+					Usage: "Pre-release identifier of the pre-release bumps (default: rc).",
+				},
+				cli.StringFlag{
+					Name:  BuildMetadataKey,
+					Usage: "Build metadata template appended to the version, e.g. {{.ShortCommit}}.",
 				},
 				cli.StringFlag{
 					Name:  GetVersionScriptKey,
@@ -85,10 +156,26 @@ var (
 					Name:  SetVersionScriptKey,
 					Usage: "Script for setting next version.",
 				},
+				cli.BoolFlag{
+					Name:  PushKey,
+					Usage: "Push the release branch, the development branch and the tag to the remote.",
+				},
+				cli.StringFlag{
+					Name:  RemoteKey,
+					Usage: "Remote to push the release to (default: origin).",
+				},
 				cli.StringFlag{
 					Name:  ChangelogPathKey,
 					Usage: "Change log path",
 				},
+				cli.StringSliceFlag{
+					Name:  IncludePathKey,
+					Usage: "Only the commits changing these paths are listed in the changelog (can be specified multiple times)",
+				},
+				cli.StringSliceFlag{
+					Name:  ExcludePathKey,
+					Usage: "Commits changing only these paths are left out from the changelog (can be specified multiple times)",
+				},
 			},
 		},
 		{
@@ -96,6 +183,10 @@ var (
 			Usage:  "Create changelog",
 			Action: createChangelog,
 			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  ComponentKey,
+					Usage: "Component to release (monorepo)",
+				},
 				cli.StringFlag{
 					Name:  DevelopmentBranchKey,
 					Usage: "Development branch",
@@ -107,7 +198,15 @@ var (
 				cli.StringFlag{
 					Name:  BumpVersionKey,
 					Value: "patch",
-					Usage: "Bump version (options: patch, minor, major).",
+					Usage: "Bump version (options: patch, minor, major, auto, prerelease, premajor, preminor, prepatch, release).",
+				},
+				cli.StringFlag{
+					Name:  PrereleaseIdentifierKey,
+					Usage: "Pre-release identifier of the pre-release bumps (default: rc).",
+				},
+				cli.StringFlag{
+					Name:  BuildMetadataKey,
+					Usage: "Build metadata template appended to the version, e.g. {{.ShortCommit}}.",
 				},
 				cli.StringFlag{
 					Name:  GetVersionScriptKey,
@@ -121,6 +220,18 @@ var (
 					Name:  ChangelogPathKey,
 					Usage: "changelog path",
 				},
+				cli.BoolFlag{
+					Name:  UnreleasedKey,
+					Usage: "Update the Unreleased section of the changelog (keepachangelog format), without releasing a version",
+				},
+				cli.StringSliceFlag{
+					Name:  IncludePathKey,
+					Usage: "Only the commits changing these paths are listed in the changelog (can be specified multiple times)",
+				},
+				cli.StringSliceFlag{
+					Name:  ExcludePathKey,
+					Usage: "Commits changing only these paths are left out from the changelog (can be specified multiple times)",
+				},
 			},
 		},
 		{
@@ -128,6 +239,10 @@ var (
 			Usage:  "Release new version",
 			Action: createRelease,
 			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  ComponentKey,
+					Usage: "Component to release (monorepo)",
+				},
 				cli.StringFlag{
 					Name:  DevelopmentBranchKey,
 					Usage: "Development branch",
@@ -143,7 +258,15 @@ var (
 				cli.StringFlag{
 					Name:  BumpVersionKey,
 					Value: "patch",
-					Usage: "Bump version (options: patch, minor, major).",
+					Usage: "Bump version (options: patch, minor, major, auto, prerelease, premajor, preminor, prepatch, release).",
+				},
+				cli.StringFlag{
+					Name:  PrereleaseIdentifierKey,
+					Usage: "Pre-release identifier of the pre-release bumps (default: rc).",
+				},
+				cli.StringFlag{
+					Name:  BuildMetadataKey,
+					Usage: "Build metadata template appended to the version, e.g. {{.ShortCommit}}.",
 				},
 				cli.StringFlag{
 					Name:  GetVersionScriptKey,
@@ -153,8 +276,150 @@ var (
 					Name:  SetVersionScriptKey,
 					Usage: "Script for setting next version.",
 				},
+				cli.BoolFlag{
+					Name:  PushKey,
+					Usage: "Push the release branch, the development branch and the tag to the remote.",
+				},
+				cli.StringFlag{
+					Name:  RemoteKey,
+					Usage: "Remote to push the release to (default: origin).",
+				},
+				cli.StringSliceFlag{
+					Name:  IncludePathKey,
+					Usage: "Only the commits changing these paths are listed in the changelog (can be specified multiple times)",
+				},
+				cli.StringSliceFlag{
+					Name:  ExcludePathKey,
+					Usage: "Commits changing only these paths are left out from the changelog (can be specified multiple times)",
+				},
+			},
+		},
+		{
+			Name:  "changelog",
+			Usage: "Manage the changelog",
+			Subcommands: []cli.Command{
+				{
+					Name:   "rebuild",
+					Usage:  "Regenerate the whole changelog from every version tag, with the current templates",
+					Action: rebuildChangelog,
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  ComponentKey,
+							Usage: "Component to release (monorepo)",
+						},
+						cli.StringFlag{
+							Name:  DevelopmentBranchKey,
+							Usage: "Development branch",
+						},
+						cli.StringFlag{
+							Name:  ReleaseBranchKey,
+							Usage: "Release branch",
+						},
+						cli.StringFlag{
+							Name:  ChangelogPathKey,
+							Usage: "changelog path",
+						},
+						cli.StringSliceFlag{
+							Name:  IncludePathKey,
+							Usage: "Only the commits changing these paths are listed in the changelog (can be specified multiple times)",
+						},
+						cli.StringSliceFlag{
+							Name:  ExcludePathKey,
+							Usage: "Commits changing only these paths are left out from the changelog (can be specified multiple times)",
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:      "notes",
+			Usage:     "Print the release notes of a version",
+			ArgsUsage: "[version]",
+			Action:    notes,
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  LatestKey,
+					Usage: "Release notes of the latest (highest) version tag",
+				},
+				cli.StringFlag{
+					Name:  StartStateKey,
+					Usage: "Release notes of the commits since this state (commit, tag or branch), the previous version tag by default",
+				},
+				cli.StringFlag{
+					Name:  EndStateKey,
+					Usage: "Release notes of the commits until this state (commit, tag or branch), the version tag or HEAD by default",
+				},
+				cli.BoolFlag{
+					Name:  FromChangelogKey,
+					Usage: "Extract the section of the version from the changelog, instead of rendering it from the commits",
+				},
+				cli.StringFlag{
+					Name:  OutputKey + ", " + outputKeyShort,
+					Usage: "Write the release notes to this file, instead of the standard output",
+				},
+				cli.StringFlag{
+					Name:  ComponentKey,
+					Usage: "Component to release (monorepo)",
+				},
+				cli.StringFlag{
+					Name:  ChangelogPathKey,
+					Usage: "changelog path",
+				},
+				cli.StringSliceFlag{
+					Name:  IncludePathKey,
+					Usage: "Only the commits changing these paths are listed in the changelog (can be specified multiple times)",
+				},
+				cli.StringSliceFlag{
+					Name:  ExcludePathKey,
+					Usage: "Commits changing only these paths are left out from the changelog (can be specified multiple times)",
+				},
+			},
+		},
+		{
+			Name:   "status",
+			Usage:  "Print the release status of the development branch",
+			Action: status,
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  FormatKey,
+					Value: TextFormat,
+					Usage: "Output format (options: text, json).",
+				},
+				cli.StringFlag{
+					Name:  ComponentKey,
+					Usage: "Component to release (monorepo)",
+				},
+				cli.StringFlag{
+					Name:  DevelopmentBranchKey,
+					Usage: "Development branch (default: the current branch)",
+				},
+				cli.StringFlag{
+					Name:  ReleaseBranchKey,
+					Usage: "Release branch",
+				},
+				cli.StringFlag{
+					Name:  PrereleaseIdentifierKey,
+					Usage: "Pre-release identifier of the pre-release bumps (default: rc).",
+				},
+				cli.StringFlag{
+					Name:  GetVersionScriptKey,
+					Usage: "Script for getting current version.",
+				},
+				cli.StringSliceFlag{
+					Name:  IncludePathKey,
+					Usage: "Only the commits changing these paths are counted as unreleased (can be specified multiple times)",
+				},
+				cli.StringSliceFlag{
+					Name:  ExcludePathKey,
+					Usage: "Commits changing only these paths are not counted as unreleased (can be specified multiple times)",
+				},
 			},
 		},
+		{
+			Name:   "rollback",
+			Usage:  "Roll back the last local release",
+			Action: rollback,
+		},
 		{
 			Name:   "init",
 			Usage:  "Initialize release configuration",
@@ -174,6 +439,16 @@ var (
 			Usage:  "If true it indicates that we're used by another tool so don't require any user input!",
 			EnvVar: CIModeEnvKey,
 		},
+		cli.BoolFlag{
+			Name:   DryRunKey,
+			Usage:  "Print the changes of the files and the git operations, without changing the repository.",
+			EnvVar: DryRunEnvKey,
+		},
+		cli.StringFlag{
+			Name:   GitBackendKey,
+			Usage:  "Git backend (options: exec, go-git). Defaults to exec if the git binary is in the PATH, go-git otherwise.",
+			EnvVar: GitBackendEnvKey,
+		},
 	}
 )
 
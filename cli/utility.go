@@ -8,16 +8,18 @@ import (
 	"strings"
 
 	log "github.com/Sirupsen/logrus"
+	"github.com/bitrise-io/go-utils/colorstring"
 	"github.com/bitrise-io/goinp/goinp"
 	"github.com/bitrise-tools/releaseman/git"
 	"github.com/bitrise-tools/releaseman/releaseman"
+	"github.com/bitrise-tools/releaseman/versioning"
 	"github.com/codegangsta/cli"
-	version "github.com/hashicorp/go-version"
 )
 
 const (
-	defaultChangelogPath       = "CHANGELOG.md"
-	defaultFirstReleaseVersion = "0.0.1"
+	defaultChangelogPath        = "CHANGELOG.md"
+	defaultRemote               = "origin"
+	defaultPrereleaseIdentifier = "rc"
 )
 
 //=======================================
@@ -41,47 +43,110 @@ func runSetVersionScript(script, nextVersion string) error {
 	return nil
 }
 
-func bumpedVersion(versionStr string, segmentIdx int) (string, error) {
-	if segmentIdx < 0 {
-		return "", fmt.Errorf("Invalid (negative) segment index: %d", segmentIdx)
+// getVersionScript returns the get version script of the flag, or of the released component.
+func getVersionScript(config releaseman.Config, c *cli.Context) string {
+	if c.IsSet(GetVersionScriptKey) {
+		return c.String(GetVersionScriptKey)
+	}
+	if config.Component != nil {
+		return config.Component.GetVersionScript
 	}
+	return ""
+}
 
-	ver, err := version.NewVersion(versionStr)
-	if err != nil {
-		return "", err
+// setVersionScript returns the set version script of the flag, or of the released component.
+func setVersionScript(config releaseman.Config, c *cli.Context) string {
+	if c.IsSet(SetVersionScriptKey) {
+		return c.String(SetVersionScriptKey)
 	}
-	verSegments := ver.Segments64()
-	if segmentIdx > len(verSegments)-1 {
-		return "", fmt.Errorf("Version does not have enough segments (segments count: %d) to increment segment at idx (%d)", len(verSegments), segmentIdx)
+	if config.Component != nil {
+		return config.Component.SetVersionScript
 	}
-	// Segments64 can be used for changing segments, but Segments() can't!!
-	//  See: https://github.com/hashicorp/go-version/issues/24
-	ver.Segments64()[segmentIdx] = verSegments[segmentIdx] + 1
-
-	return ver.String(), nil
+	return ""
 }
 
-func validateVersion(versionStr string) error {
-	_, err := version.NewVersion(versionStr)
-	if err != nil {
+// setVersion runs the set version script and updates the version files.
+func setVersion(config releaseman.Config, c *cli.Context) error {
+	if versionScript := setVersionScript(config, c); versionScript != "" {
+		if releaseman.IsDryRun {
+			log.Infof("[dry-run] next_version=%s %s", config.Release.Version, versionScript)
+		} else if err := runSetVersionScript(versionScript, config.Release.Version); err != nil {
+			return err
+		}
+	}
+	if err := releaseman.WriteVersionFiles(config.Release.VersionFiles, config.Release.Version); err != nil {
 		return err
 	}
+
+	for _, file := range config.Release.VersionFiles {
+		buildNumber, err := file.ReadBuildNumber()
+		if err != nil {
+			return err
+		}
+		if buildNumber != "" {
+			log.Infof("Build number of %s: %s", file.Path, buildNumber)
+		}
+	}
 	return nil
 }
 
-func versionSegmentIdx(segmentStr string) (int, error) {
-	segmentIdx := 0
-	switch segmentStr {
-	case PatchKey:
-		segmentIdx = 2
-	case MinorKey:
-		segmentIdx = 1
-	case MajorKey:
-		segmentIdx = 0
-	default:
-		return -1, fmt.Errorf("Invalid segment name (%s)", segmentStr)
-	}
-	return segmentIdx, nil
+// withBuildMetadata appends the build metadata, rendered from the template, to the version.
+// Versions with build metadata are kept as they are.
+func withBuildMetadata(versionStr, metadataTemplate string) (string, error) {
+	if metadataTemplate == "" || strings.Contains(versionStr, "+") {
+		return versionStr, nil
+	}
+
+	latestCommit, err := git.LatestCommit()
+	if err != nil {
+		return "", err
+	}
+
+	metadata, err := releaseman.RenderBuildMetadata(metadataTemplate, latestCommit)
+	if err != nil {
+		return "", err
+	}
+	return versionStr + "+" + metadata, nil
+}
+
+// autoBumpSegment picks the version segment to bump based on the Conventional Commits types:
+// breaking changes bump the major, features the minor and fixes the patch version.
+// It returns an empty segment if none of the commits is releasable.
+func autoBumpSegment(commits []git.CommitModel) (string, string) {
+	breakingChanges := []git.CommitModel{}
+	features := []git.CommitModel{}
+	fixes := []git.CommitModel{}
+
+	for _, commit := range commits {
+		if commit.Breaking {
+			breakingChanges = append(breakingChanges, commit)
+		} else if commit.Type == "feat" {
+			features = append(features, commit)
+		} else if commit.Type == "fix" {
+			fixes = append(fixes, commit)
+		}
+	}
+
+	reason := func(kind string, relevantCommits []git.CommitModel) string {
+		return fmt.Sprintf("%d %s since the last release, e.g. [%s] %s", len(relevantCommits), kind, firstChars(relevantCommits[0].Hash, 7), relevantCommits[0].Message)
+	}
+
+	switch {
+	case len(breakingChanges) > 0:
+		return MajorKey, reason("breaking change(s)", breakingChanges)
+	case len(features) > 0:
+		return MinorKey, reason("feature(s)", features)
+	case len(fixes) > 0:
+		return PatchKey, reason("fix(es)", fixes)
+	}
+	return "", fmt.Sprintf("none of the %d commit(s) since the last release is a breaking change, feature or fix", len(commits))
+}
+
+func firstChars(str string, length int) string {
+	if len(str) < length {
+		return str
+	}
+	return str[0:length]
 }
 
 //=======================================
@@ -155,14 +220,14 @@ func askForReleaseBranch() (string, error) {
 	return answer, nil
 }
 
-func askForReleaseVersion() (string, error) {
+func askForReleaseVersion(defaultVersion string) (string, error) {
 	fmt.Println()
-	answer, err := goinp.AskForStringWithDefault("Type in the new release version!", defaultFirstReleaseVersion)
+	answer, err := goinp.AskForStringWithDefault("Type in the new release version!", defaultVersion)
 	if err != nil {
 		return "", err
 	}
 	if answer == "" {
-		answer = defaultFirstReleaseVersion
+		answer = defaultVersion
 	}
 	return answer, nil
 }
@@ -183,6 +248,42 @@ func askForChangelogPath() (string, error) {
 // Fill config
 //=======================================
 
+func fillComponent(config releaseman.Config, c *cli.Context) (releaseman.Config, error) {
+	var err error
+
+	if len(config.Components) == 0 {
+		if c.IsSet(ComponentKey) {
+			return releaseman.Config{}, errors.New("No components defined in the release config")
+		}
+		return config, nil
+	}
+
+	component := c.String(ComponentKey)
+	if component == "" {
+		if releaseman.IsCIMode {
+			return releaseman.Config{}, errors.New("Missing required input: component")
+		}
+
+		fmt.Println()
+		component, err = goinp.SelectFromStrings("Select the component to release!", config.ComponentNames())
+		if err != nil {
+			return releaseman.Config{}, err
+		}
+	}
+
+	return config.WithComponent(component)
+}
+
+func fillChangelogPathFilter(config releaseman.Config, c *cli.Context) releaseman.Config {
+	if c.IsSet(IncludePathKey) {
+		config.Changelog.IncludePaths = c.StringSlice(IncludePathKey)
+	}
+	if c.IsSet(ExcludePathKey) {
+		config.Changelog.ExcludePaths = c.StringSlice(ExcludePathKey)
+	}
+	return config
+}
+
 func fillDevelopmetnBranch(config releaseman.Config, c *cli.Context) (releaseman.Config, error) {
 	var err error
 
@@ -231,44 +332,74 @@ func fillReleaseBranch(config releaseman.Config, c *cli.Context) (releaseman.Con
 	return config, nil
 }
 
-func fillVersion(config releaseman.Config, c *cli.Context) (releaseman.Config, error) {
-	var err error
-
-	tags, err := git.VersionTaggedCommits()
-	if err != nil {
-		return releaseman.Config{}, err
-	}
-
-	currentVersion := ""
-	if c.IsSet(GetVersionScriptKey) {
+// readCurrentVersion returns the output of the get version script, the version stored in the version files,
+// or the version of the last tagged commit of the development and release branches (tags), in this order.
+func readCurrentVersion(config releaseman.Config, c *cli.Context, tags []git.CommitModel) (string, error) {
+	if versionScript := getVersionScript(config, c); versionScript != "" {
 		log.Infof("Get version script provided")
-		versionScript := c.String(GetVersionScriptKey)
 		parts := strings.Fields(versionScript)
 		head := parts[0]
 		parts = parts[1:len(parts)]
 
 		outBytes, err := exec.Command(head, parts...).CombinedOutput()
 		if err != nil {
-			return releaseman.Config{}, fmt.Errorf("Failed to run bump script, out: %s, error: %#v", string(outBytes), err)
+			return "", fmt.Errorf("Failed to run bump script, out: %s, error: %#v", string(outBytes), err)
 		}
-		versionStr := string(outBytes)
-		versionStr = git.Strip(versionStr)
+		return git.Strip(string(outBytes)), nil
+	}
 
-		currentVersion = versionStr
+	if len(config.Release.VersionFiles) > 0 {
+		log.Infof("Version files provided")
+		return releaseman.ReadVersionFiles(config.Release.VersionFiles)
+	}
 
-	} else if len(tags) > 0 {
-		currentVersion = tags[len(tags)-1].Tag
+	if len(tags) > 0 {
+		return tags[len(tags)-1].Version, nil
+	}
+	return "", nil
+}
+
+func fillVersion(config releaseman.Config, c *cli.Context) (releaseman.Config, error) {
+	var err error
+
+	if c.IsSet(PrereleaseIdentifierKey) {
+		config.Release.PrereleaseIdentifier = c.String(PrereleaseIdentifierKey)
+	}
+	if c.IsSet(BuildMetadataKey) {
+		config.Release.BuildMetadata = c.String(BuildMetadataKey)
+	}
+
+	scheme, err := config.VersionScheme()
+	if err != nil {
+		return releaseman.Config{}, err
+	}
+	bumpOptions := versioning.BumpOptions{PrereleaseIdentifier: config.Release.PrereleaseIdentifier}
+
+	allTags, err := git.VersionTaggedCommits(config.Release.TagFormat, scheme)
+	if err != nil {
+		return releaseman.Config{}, err
+	}
+
+	// The current version is the highest version reachable from the development or the release branch,
+	// the release tag is on the merge commit of the release branch
+	tags, err := git.BranchVersionTaggedCommits(config.Release.TagFormat, scheme, config.Release.DevelopmentBranch, config.Release.ReleaseBranch)
+	if err != nil {
+		return releaseman.Config{}, err
+	}
+
+	currentVersion, err := readCurrentVersion(config, c, tags)
+	if err != nil {
+		return releaseman.Config{}, err
 	}
 
 	if currentVersion != "" {
 		log.Infof("Current version: %s", currentVersion)
+	}
 
-		segmentIdx, err := versionSegmentIdx(PatchKey)
-		if err != nil {
-			return releaseman.Config{}, err
-		}
-
-		config.Release.Version, err = bumpedVersion(currentVersion, segmentIdx)
+	// the patch bump is the default, it is not computed if the version is given,
+	// as the version scheme may not support it (e.g. a calendar version without MICRO)
+	if currentVersion != "" && !c.IsSet(BumpVersionKey) && !c.IsSet(VersionKey) {
+		config.Release.Version, err = scheme.Bump(currentVersion, PatchKey, bumpOptions)
 		if err != nil {
 			return releaseman.Config{}, err
 		}
@@ -280,16 +411,40 @@ func fillVersion(config releaseman.Config, c *cli.Context) (releaseman.Config, e
 			return releaseman.Config{}, errors.New("Current version not found, nothing to bump")
 		}
 
-		segmentIdx, err := versionSegmentIdx(c.String(BumpVersionKey))
-		if err != nil {
-			return releaseman.Config{}, err
+		segment := c.String(BumpVersionKey)
+		if segment == AutoKey {
+			var startCommitPtr *git.CommitModel
+			if len(tags) > 0 {
+				startCommitPtr = &(tags[len(tags)-1])
+			}
+
+			commits, err := git.GetCommitsFrom(startCommitPtr)
+			if err != nil {
+				return releaseman.Config{}, err
+			}
+			commits = config.FilterCommits(commits)
+
+			reason := ""
+			segment, reason = autoBumpSegment(commits)
+			if segment == "" {
+				if releaseman.IsCIMode {
+					return releaseman.Config{}, fmt.Errorf("No releasable commits found: %s", reason)
+				}
+
+				log.Warnf("No releasable commits found: %s", reason)
+				config.Release.Version = ""
+			} else {
+				log.Infof("Automatic version bump: %s, because of %s", segment, reason)
+			}
 		}
 
-		log.Infof("Bumping version %s part", c.String(BumpVersionKey))
+		if segment != "" {
+			log.Infof("Bumping version: %s", segment)
 
-		config.Release.Version, err = bumpedVersion(currentVersion, segmentIdx)
-		if err != nil {
-			return releaseman.Config{}, err
+			config.Release.Version, err = scheme.Bump(currentVersion, segment, bumpOptions)
+			if err != nil {
+				return releaseman.Config{}, err
+			}
 		}
 	} else if c.IsSet(VersionKey) {
 		config.Release.Version = c.String(VersionKey)
@@ -305,15 +460,17 @@ func fillVersion(config releaseman.Config, c *cli.Context) (releaseman.Config, e
 			log.Infof("Your previous tag: %s", tags[len(tags)-1].Tag)
 		}
 
-		version, err := askForReleaseVersion()
-		if err != nil {
-			return releaseman.Config{}, err
+		// an existing project continues with the next patch version, instead of the first version
+		defaultVersion := scheme.First(bumpOptions)
+		if currentVersion != "" {
+			if patchVersion, err := scheme.Bump(currentVersion, PatchKey, bumpOptions); err == nil {
+				defaultVersion = patchVersion
+			}
 		}
 
-		for _, taggedCommit := range tags {
-			if taggedCommit.Tag == version {
-				return releaseman.Config{}, fmt.Errorf("Tag (%s) already exist", version)
-			}
+		version, err := askForReleaseVersion(defaultVersion)
+		if err != nil {
+			return releaseman.Config{}, err
 		}
 
 		config.Release.Version = version
@@ -323,10 +480,22 @@ func fillVersion(config releaseman.Config, c *cli.Context) (releaseman.Config, e
 		return releaseman.Config{}, errors.New("Missing required input: release version")
 	}
 
-	if err := validateVersion(config.Release.Version); err != nil {
+	if err := scheme.Validate(config.Release.Version); err != nil {
 		return releaseman.Config{}, err
 	}
 
+	if config.Release.Version, err = withBuildMetadata(config.Release.Version, config.Release.BuildMetadata); err != nil {
+		return releaseman.Config{}, err
+	}
+
+	// The version can be released on another maintenance line already,
+	// versions differing only in build metadata are the same version
+	for _, taggedCommit := range allTags {
+		if cmp, err := scheme.Compare(taggedCommit.Version, config.Release.Version); err == nil && cmp == 0 {
+			return releaseman.Config{}, fmt.Errorf("Tag (%s) already exist", taggedCommit.Tag)
+		}
+	}
+
 	return config, nil
 }
 
@@ -354,6 +523,20 @@ func fillChangelogPath(config releaseman.Config, c *cli.Context) (releaseman.Con
 	return config, nil
 }
 
+func fillPush(config releaseman.Config, c *cli.Context) (releaseman.Config, error) {
+	if c.Bool(PushKey) {
+		config.Release.Push = true
+	}
+	if c.IsSet(RemoteKey) {
+		config.Release.Remote = c.String(RemoteKey)
+	}
+	if config.Release.Push && config.Release.Remote == "" {
+		config.Release.Remote = defaultRemote
+	}
+
+	return config, nil
+}
+
 //=======================================
 // Ensure
 //=======================================
@@ -367,6 +550,31 @@ func ensureCleanGit() error {
 	return nil
 }
 
+// ensureNotBehindRemote fails if the remote has commits on the development or release branch,
+// which are not in the local branch.
+func ensureNotBehindRemote(config releaseman.Config) error {
+	for _, branch := range []string{config.Release.DevelopmentBranch, config.Release.ReleaseBranch} {
+		remoteHead, err := git.RemoteBranchHead(config.Release.Remote, branch)
+		if err != nil {
+			return err
+		}
+		if remoteHead == "" {
+			continue
+		}
+
+		localHead, err := git.BranchHead(branch)
+		if err != nil {
+			return err
+		}
+		if isAncestor, err := git.IsAncestor(remoteHead, localHead); err != nil {
+			return err
+		} else if !isAncestor {
+			return fmt.Errorf("Branch (%s) is behind %s/%s, please pull the remote changes before continue release", branch, config.Release.Remote, branch)
+		}
+	}
+	return nil
+}
+
 func ensureCurrentBranch(config releaseman.Config) error {
 	currentBranch, err := git.CurrentBranchName()
 	if err != nil {
@@ -374,7 +582,8 @@ func ensureCurrentBranch(config releaseman.Config) error {
 	}
 
 	if config.Release.DevelopmentBranch != currentBranch {
-		if releaseman.IsCIMode {
+		// the dry run can not check out the development branch
+		if releaseman.IsCIMode || releaseman.IsDryRun {
 			return fmt.Errorf("Your current branch (%s), should be the development branch (%s)", currentBranch, config.Release.DevelopmentBranch)
 		}
 
@@ -402,20 +611,11 @@ func ensureCurrentBranch(config releaseman.Config) error {
 // Print common messages
 //=======================================
 
-func printRollBackMessage() {
-	fmt.Println()
-	log.Infoln("How to roll-back?")
-	log.Infoln("* if you want to undo the last commit you can call:")
-	log.Infoln("    $ git reset --hard HEAD~1")
-	log.Infoln("* to delete tag:")
-	log.Infoln("    $ git tag -d [TAG]")
-	log.Infoln("    $ git push origin :refs/tags/[TAG]")
-	log.Infoln("* to roll back to the remote state:")
-	log.Infoln("    $ git reset --hard origin/[branch-name]")
-	fmt.Println()
-}
-
 func printCollectingCommits(startCommit *git.CommitModel, nextVersion string) {
+	if nextVersion == "" {
+		nextVersion = "unreleased"
+	}
+
 	fmt.Println()
 	if startCommit != nil && startCommit.Tag != "" {
 		log.Infof("Collecting commits between (%s - %s)", startCommit.Tag, nextVersion)
@@ -423,3 +623,26 @@ func printCollectingCommits(startCommit *git.CommitModel, nextVersion string) {
 		log.Infof("Collecting commits between (initial commit - %s)", nextVersion)
 	}
 }
+
+// printDryRunSummary prints the computed version, the files and the git operations of the dry run.
+func printDryRunSummary(config releaseman.Config) {
+	fmt.Println()
+	log.Infoln(colorstring.Yellow("Dry run finished, nothing was changed"))
+	if config.Release.Version != "" {
+		log.Infof("Version: %s", config.Release.Version)
+	}
+
+	if files := releaseman.DryRunChangedFiles(); len(files) > 0 {
+		log.Infof("Files to change:")
+		for _, file := range files {
+			log.Infof(" * %s", file)
+		}
+	}
+
+	if repository, ok := git.CurrentRepository().(*git.DryRunRepository); ok && len(repository.Operations) > 0 {
+		log.Infof("Git operations:")
+		for _, operation := range repository.Operations {
+			log.Infof(" * %s", operation)
+		}
+	}
+}
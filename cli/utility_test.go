@@ -3,47 +3,57 @@ package cli
 import (
 	"testing"
 
+	"github.com/bitrise-tools/releaseman/git"
+	"github.com/bitrise-tools/releaseman/versioning"
 	"github.com/stretchr/testify/require"
 )
 
-func TestBumpVersion(t *testing.T) {
-	ver, err := bumpedVersion("", -1)
-	require.EqualError(t, err, "Invalid (negative) segment index: -1")
-	require.Equal(t, "", ver)
-
-	ver, err = bumpedVersion("", 0)
-	require.EqualError(t, err, "Malformed version: ")
-	require.Equal(t, "", ver)
-
-	ver, err = bumpedVersion("-1", 0)
-	require.EqualError(t, err, "Malformed version: -1")
-	require.Equal(t, "", ver)
-
-	ver, err = bumpedVersion("1.0.0", 12)
-	require.EqualError(t, err, "Version does not have enough segments (segments count: 3) to increment segment at idx (12)")
-	require.Equal(t, "", ver)
-
-	ver, err = bumpedVersion("1", 0)
-	require.NoError(t, err)
-	require.Equal(t, "2.0.0", ver)
-
-	ver, err = bumpedVersion("1.1", 0)
-	require.NoError(t, err)
-	require.Equal(t, "2.1.0", ver)
-
-	ver, err = bumpedVersion("1.1", 1)
-	require.NoError(t, err)
-	require.Equal(t, "1.2.0", ver)
-
-	ver, err = bumpedVersion("1.1.1", 0)
-	require.NoError(t, err)
-	require.Equal(t, "2.1.1", ver)
-
-	ver, err = bumpedVersion("1.1.1", 1)
-	require.NoError(t, err)
-	require.Equal(t, "1.2.1", ver)
-
-	ver, err = bumpedVersion("1.1.1", 2)
-	require.NoError(t, err)
-	require.Equal(t, "1.1.2", ver)
+func TestAutoBumpSegment(t *testing.T) {
+	t.Log("Test breaking change")
+	{
+		segment, reason := autoBumpSegment([]git.CommitModel{
+			git.CommitModel{Hash: "85d8658733f73ae6d5407e8e4c2b81a5f2ed016c", Type: "fix", Message: "fix: typo"},
+			git.CommitModel{Hash: "7d3243a6e91aa46f28ed3811bb4bc26a05ce0b02", Type: "feat", Message: "feat!: new config", Breaking: true},
+			git.CommitModel{Hash: "b738dee2d32def019a4d553249004364046dc1bd", Type: "feat", Message: "feat: auto bump"},
+		})
+		require.Equal(t, MajorKey, segment)
+		require.Equal(t, "1 breaking change(s) since the last release, e.g. [7d3243a] feat!: new config", reason)
+	}
+
+	t.Log("Test feature")
+	{
+		segment, reason := autoBumpSegment([]git.CommitModel{
+			git.CommitModel{Hash: "85d8658733f73ae6d5407e8e4c2b81a5f2ed016c", Type: "fix", Message: "fix: typo"},
+			git.CommitModel{Hash: "b738dee2d32def019a4d553249004364046dc1bd", Type: "feat", Message: "feat: auto bump"},
+		})
+		require.Equal(t, MinorKey, segment)
+		require.Equal(t, "1 feature(s) since the last release, e.g. [b738dee] feat: auto bump", reason)
+
+		// the lower segments restart from zero
+		version, err := versioning.SemVer{}.Bump("1.4.2", segment, versioning.BumpOptions{})
+		require.NoError(t, err)
+		require.Equal(t, "1.5.0", version)
+	}
+
+	t.Log("Test fix")
+	{
+		segment, _ := autoBumpSegment([]git.CommitModel{
+			git.CommitModel{Hash: "85d8658733f73ae6d5407e8e4c2b81a5f2ed016c", Type: "fix", Message: "fix: typo"},
+			git.CommitModel{Hash: "b738dee2d32def019a4d553249004364046dc1bd", Type: "docs", Message: "docs: readme"},
+		})
+		require.Equal(t, PatchKey, segment)
+	}
+
+	t.Log("Test no releasable commits")
+	{
+		segment, reason := autoBumpSegment([]git.CommitModel{
+			git.CommitModel{Hash: "b738dee2d32def019a4d553249004364046dc1bd", Type: "docs", Message: "docs: readme"},
+			git.CommitModel{Hash: "85d8658733f73ae6d5407e8e4c2b81a5f2ed016c", Message: "Merge branch 'master'"},
+		})
+		require.Equal(t, "", segment)
+		require.Equal(t, "none of the 2 commit(s) since the last release is a breaking change, feature or fix", reason)
+
+		segment, _ = autoBumpSegment([]git.CommitModel{})
+		require.Equal(t, "", segment)
+	}
 }
@@ -1,10 +1,12 @@
 package git
 
 import (
+	"bytes"
+	"fmt"
+	"os/exec"
 	"strings"
 
 	log "github.com/Sirupsen/logrus"
-	"github.com/bitrise-io/go-utils/command"
 )
 
 //=======================================
@@ -18,6 +20,32 @@ type PrintableCommand struct {
 	Args       []string
 }
 
+// CommandError describes a failed command, it is returned by PrintableCommand.Run.
+type CommandError struct {
+	Command  string
+	ExitCode int
+	Stdout   string
+	Stderr   string
+	Err      error
+}
+
+// Error ...
+func (commandError *CommandError) Error() string {
+	output := strings.TrimSpace(commandError.Stderr)
+	if output == "" {
+		output = strings.TrimSpace(commandError.Stdout)
+	}
+	if output == "" {
+		output = commandError.Err.Error()
+	}
+	return fmt.Sprintf("command (%s) failed with exit code %d: %s", commandError.Command, commandError.ExitCode, output)
+}
+
+// Unwrap ...
+func (commandError *CommandError) Unwrap() error {
+	return commandError.Err
+}
+
 // NewPrintableCommand ...
 func NewPrintableCommand(commandParts ...string) PrintableCommand {
 	name := commandParts[0]
@@ -33,17 +61,38 @@ func NewPrintableCommand(commandParts ...string) PrintableCommand {
 	}
 }
 
-// Run ...
+// Run returns the trimmed standard output of the command,
+// or a *CommandError if the command could not be started or exited with non zero code.
 func (printableCommand PrintableCommand) Run() (string, error) {
 	log.Debugf("=> (%#v)", printableCommand)
 
-	out, err := command.RunCommandAndReturnCombinedStdoutAndStderr(printableCommand.Name, printableCommand.Args...)
-	if err != nil {
-		log.Fatalf("Failed to execute:\ncommand:(%s),\noutput:(%s),\nerror:(%#v)", printableCommand.RawCommand, out, err)
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(printableCommand.Name, printableCommand.Args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		exitCode := -1
+		if exitError, ok := err.(*exec.ExitError); ok {
+			exitCode = exitError.ExitCode()
+		}
+
+		commandError := &CommandError{
+			Command:  printableCommand.RawCommand,
+			ExitCode: exitCode,
+			Stdout:   stdout.String(),
+			Stderr:   stderr.String(),
+			Err:      err,
+		}
+		log.Debugf("Failed to execute: %s", commandError)
+
+		return strings.TrimSpace(stdout.String()), commandError
 	}
+
+	out := strings.TrimSpace(stdout.String())
 	log.Debugf("output:\n(%s)", out)
 
-	return out, err
+	return out, nil
 }
 
 //=======================================
@@ -28,6 +28,31 @@ func TestRun(t *testing.T) {
 	out, err := command.Run()
 	require.Equal(t, nil, err)
 	require.Equal(t, "Hello World!", out)
+
+	t.Log("Test failing command")
+	{
+		command := NewPrintableCommand("sh", "-c", "echo out; echo err >&2; exit 3")
+		out, err := command.Run()
+		require.Equal(t, "out", out)
+
+		commandError, ok := err.(*CommandError)
+		require.Equal(t, true, ok)
+		require.Equal(t, "sh -c echo out; echo err >&2; exit 3", commandError.Command)
+		require.Equal(t, 3, commandError.ExitCode)
+		require.Equal(t, "out\n", commandError.Stdout)
+		require.Equal(t, "err\n", commandError.Stderr)
+		require.EqualError(t, err, "command (sh -c echo out; echo err >&2; exit 3) failed with exit code 3: err")
+	}
+
+	t.Log("Test missing executable")
+	{
+		_, err := NewPrintableCommand("releaseman-missing-executable").Run()
+
+		commandError, ok := err.(*CommandError)
+		require.Equal(t, true, ok)
+		require.Equal(t, -1, commandError.ExitCode)
+		require.NotEqual(t, nil, commandError.Unwrap())
+	}
 }
 
 func TestSplitByNewLineAndStrip(t *testing.T) {
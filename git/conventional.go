@@ -0,0 +1,93 @@
+package git
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	breakingChangeToken    = "BREAKING CHANGE"
+	breakingChangeAltToken = "BREAKING-CHANGE"
+)
+
+var (
+	// type(scope)!: description
+	conventionalHeaderRegexp = regexp.MustCompile(`^([a-zA-Z]+)(?:\(([^()]*)\))?(!)?: (.+)$`)
+	// Token: value or Token #value, see git trailer convention
+	conventionalFooterRegexp = regexp.MustCompile(`^(BREAKING CHANGE|BREAKING-CHANGE|[a-zA-Z-]+)(?:: | #)(.*)$`)
+)
+
+//=======================================
+// Models
+//=======================================
+
+// CommitFooterModel ...
+type CommitFooterModel struct {
+	Token string
+	Value string
+}
+
+//=======================================
+// Utility
+//=======================================
+
+// IsConventional ...
+func (commit CommitModel) IsConventional() bool {
+	return commit.Type != ""
+}
+
+func parseConventionalCommit(commit CommitModel) CommitModel {
+	commit.Type = ""
+	commit.Scope = ""
+	commit.Breaking = false
+	commit.Description = commit.Message
+	commit.Footers = parseFooters(commit.Body)
+
+	for _, footer := range commit.Footers {
+		if footer.Token == breakingChangeToken || footer.Token == breakingChangeAltToken {
+			commit.Breaking = true
+		}
+	}
+
+	match := conventionalHeaderRegexp.FindStringSubmatch(strings.TrimSpace(commit.Message))
+	if match == nil {
+		return commit
+	}
+
+	commit.Type = strings.ToLower(match[1])
+	commit.Scope = match[2]
+	commit.Breaking = commit.Breaking || (match[3] == "!")
+	commit.Description = match[4]
+
+	return commit
+}
+
+// parseFooters parses the last paragraph of the body, if every line of it is a footer
+// (a continuation line belongs to the previous footer).
+func parseFooters(body string) []CommitFooterModel {
+	paragraphs := strings.Split(strings.TrimSpace(body), "\n\n")
+	lastParagraph := paragraphs[len(paragraphs)-1]
+	if lastParagraph == "" {
+		return []CommitFooterModel{}
+	}
+
+	footers := []CommitFooterModel{}
+	for idx, line := range strings.Split(lastParagraph, "\n") {
+		match := conventionalFooterRegexp.FindStringSubmatch(line)
+		if match == nil {
+			if idx == 0 {
+				return []CommitFooterModel{}
+			}
+
+			footers[len(footers)-1].Value += "\n" + line
+			continue
+		}
+
+		footers = append(footers, CommitFooterModel{
+			Token: match[1],
+			Value: match[2],
+		})
+	}
+
+	return footers
+}
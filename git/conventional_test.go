@@ -0,0 +1,98 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseConventionalCommit(t *testing.T) {
+	t.Log("Test not conventional commit")
+	{
+		commit := parseConventionalCommit(CommitModel{Message: "Merge branch 'master' of github.com:bitrise-tools/releaseman"})
+		require.Equal(t, false, commit.IsConventional())
+		require.Equal(t, "", commit.Type)
+		require.Equal(t, false, commit.Breaking)
+		require.Equal(t, "Merge branch 'master' of github.com:bitrise-tools/releaseman", commit.Description)
+	}
+
+	t.Log("Test upper case type")
+	{
+		commit := parseConventionalCommit(CommitModel{Message: "FIX: parsing git commits"})
+		require.Equal(t, true, commit.IsConventional())
+		require.Equal(t, "fix", commit.Type)
+		require.Equal(t, "parsing git commits", commit.Description)
+	}
+
+	t.Log("Test conventional commit")
+	{
+		commit := parseConventionalCommit(CommitModel{Message: "feat: changelog sections"})
+		require.Equal(t, true, commit.IsConventional())
+		require.Equal(t, "feat", commit.Type)
+		require.Equal(t, "", commit.Scope)
+		require.Equal(t, false, commit.Breaking)
+		require.Equal(t, "changelog sections", commit.Description)
+	}
+
+	t.Log("Test conventional commit with scope and breaking mark")
+	{
+		commit := parseConventionalCommit(CommitModel{Message: "Fix(git)!: drop date based commit collection"})
+		require.Equal(t, "fix", commit.Type)
+		require.Equal(t, "git", commit.Scope)
+		require.Equal(t, true, commit.Breaking)
+		require.Equal(t, "drop date based commit collection", commit.Description)
+	}
+
+	t.Log("Test conventional commit with footers")
+	{
+		commit := parseConventionalCommit(CommitModel{
+			Message: "refactor(cli): new config format",
+			Body: `The release section moved.
+
+BREAKING CHANGE: release_config.yml has to be
+regenerated with releaseman init
+Refs #12
+Reviewed-by: Viktor Benei`,
+		})
+		require.Equal(t, "refactor", commit.Type)
+		require.Equal(t, true, commit.Breaking)
+		require.Equal(t, []CommitFooterModel{
+			CommitFooterModel{Token: "BREAKING CHANGE", Value: "release_config.yml has to be\nregenerated with releaseman init"},
+			CommitFooterModel{Token: "Refs", Value: "12"},
+			CommitFooterModel{Token: "Reviewed-by", Value: "Viktor Benei"},
+		}, commit.Footers)
+	}
+
+	t.Log("Test body without footers")
+	{
+		commit := parseConventionalCommit(CommitModel{
+			Message: "docs: readme",
+			Body:    "Note that the readme: is long",
+		})
+		require.Equal(t, []CommitFooterModel{}, commit.Footers)
+		require.Equal(t, false, commit.Breaking)
+	}
+}
+
+func TestParseCommitWithBody(t *testing.T) {
+	commitLine := `commit 85d8658733f73ae6d5407e8e4c2b81a5f2ed016c
+commit: 85d8658733f73ae6d5407e8e4c2b81a5f2ed016c
+date: 1455631980
+author: Krisztián Gödrei
+parents: 7d3243a6e91aa46f28ed3811bb4bc26a05ce0b02
+message: feat(changelog): sections
+body:
+    Commits are grouped by type.
+
+    BREAKING CHANGE: content_template gets .Sections
+`
+
+	commit, err := parseCommit(commitLine)
+	require.Equal(t, nil, err)
+	require.Equal(t, "feat(changelog): sections", commit.Message)
+	require.Equal(t, "Commits are grouped by type.\n\nBREAKING CHANGE: content_template gets .Sections", commit.Body)
+	require.Equal(t, "feat", commit.Type)
+	require.Equal(t, "changelog", commit.Scope)
+	require.Equal(t, "sections", commit.Description)
+	require.Equal(t, true, commit.Breaking)
+}
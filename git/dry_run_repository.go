@@ -0,0 +1,160 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+//=======================================
+// Model
+//=======================================
+
+// DryRunRepository forwards the queries to the wrapped repository, while the operations changing the repository
+// (or the remote) are only printed and recorded.
+type DryRunRepository struct {
+	Repository
+
+	// Operations are the git commands of the skipped operations, in order
+	Operations []string
+
+	changedFiles  func() []string
+	currentBranch string
+}
+
+// NewDryRunRepository wraps the repository, changedFiles returns the files which would have been changed
+// by the dry run, they are listed as changed files (nil means no file is changed).
+func NewDryRunRepository(repo Repository, changedFiles func() []string) *DryRunRepository {
+	return &DryRunRepository{
+		Repository:   repo,
+		Operations:   []string{},
+		changedFiles: changedFiles,
+	}
+}
+
+//=======================================
+// Utility
+//=======================================
+
+func (repo *DryRunRepository) record(commandParts ...string) {
+	operation := strings.Join(commandParts, " ")
+	repo.Operations = append(repo.Operations, operation)
+	log.Infof("[dry-run] %s", operation)
+}
+
+//=======================================
+// Repository
+//=======================================
+
+// CurrentBranchName returns the branch checked out by the dry run, or the current branch.
+func (repo *DryRunRepository) CurrentBranchName() (string, error) {
+	if repo.currentBranch != "" {
+		return repo.currentBranch, nil
+	}
+	return repo.Repository.CurrentBranchName()
+}
+
+// GetChangedFiles returns the changed files of the working tree and the files changed by the dry run.
+func (repo *DryRunRepository) GetChangedFiles() ([]string, error) {
+	changes, err := repo.Repository.GetChangedFiles()
+	if err != nil {
+		return []string{}, err
+	}
+	if repo.changedFiles == nil {
+		return changes, nil
+	}
+
+	for _, file := range repo.changedFiles() {
+		listed := false
+		for _, change := range changes {
+			if change == file {
+				listed = true
+			}
+		}
+		if !listed {
+			changes = append(changes, file)
+		}
+	}
+	return changes, nil
+}
+
+// CheckoutBranch ...
+func (repo *DryRunRepository) CheckoutBranch(branch string) error {
+	repo.record("git", "checkout", branch)
+	repo.currentBranch = branch
+	return nil
+}
+
+// ResetBranch ...
+func (repo *DryRunRepository) ResetBranch(branch, hash string) error {
+	repo.record("git", "branch", "--force", branch, hash)
+	return nil
+}
+
+// DeleteTag ...
+func (repo *DryRunRepository) DeleteTag(tag string) error {
+	repo.record("git", "tag", "--delete", tag)
+	return nil
+}
+
+// Push ...
+func (repo *DryRunRepository) Push(remote string, branches, tags []string) error {
+	commandParts := []string{"git", "push", "--atomic", remote}
+	commandParts = append(commandParts, branches...)
+	commandParts = append(commandParts, tags...)
+	repo.record(commandParts...)
+	return nil
+}
+
+// Add ...
+func (repo *DryRunRepository) Add(files []string) error {
+	for _, file := range files {
+		repo.record("git", "add", file)
+	}
+	return nil
+}
+
+// Commit ...
+func (repo *DryRunRepository) Commit(message string, options CommitOptions) error {
+	commandParts, err := options.gitCommand("commit", "-m", fmt.Sprintf("%q", message))
+	if err != nil {
+		return err
+	}
+	repo.record(commandParts...)
+	return nil
+}
+
+// Merge ...
+func (repo *DryRunRepository) Merge(branch, commitMessage string, options CommitOptions) error {
+	commandParts, err := options.gitCommand("merge", branch, "--no-ff", "-m", fmt.Sprintf("%q", commitMessage))
+	if err != nil {
+		return err
+	}
+	repo.record(commandParts...)
+	return nil
+}
+
+// Tag ...
+func (repo *DryRunRepository) Tag(name string, options TagOptions) error {
+	if err := options.Validate(); err != nil {
+		return err
+	}
+
+	commandParts := []string{"git"}
+	if options.Sign != nil {
+		commandParts = append(commandParts, options.Sign.gitConfigArgs()...)
+	}
+	commandParts = append(commandParts, "tag")
+	if options.Message != "" {
+		if options.Sign != nil {
+			commandParts = append(commandParts, "--sign")
+		} else {
+			commandParts = append(commandParts, "--annotate")
+		}
+		// the message of the annotated tag is the release notes, only its first line is printed
+		commandParts = append(commandParts, "--cleanup=whitespace", "-m", fmt.Sprintf("%q", strings.SplitN(options.Message, "\n", 2)[0]))
+	}
+	repo.record(append(commandParts, name)...)
+	return nil
+}
@@ -0,0 +1,72 @@
+package git
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/util"
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDryRunRepository(t *testing.T) {
+	gitRepo, fs := newMemoryRepository(t)
+	worktree, err := gitRepo.Worktree()
+	require.NoError(t, err)
+
+	initialHash := commitFile(t, gitRepo, fs, "README.md", "feat: initial", time.Unix(1454498663, 0))
+	require.NoError(t, worktree.Checkout(&gogit.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("release"), Create: true}))
+	require.NoError(t, worktree.Checkout(&gogit.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("master")}))
+	require.NoError(t, util.WriteFile(fs, "README.md", []byte("changed"), 0644))
+
+	repo := NewDryRunRepository(NewGoGitRepository(gitRepo), func() []string { return []string{"README.md", "CHANGELOG.md"} })
+
+	t.Log("the changed files of the dry run are listed")
+	{
+		changes, err := repo.GetChangedFiles()
+		require.NoError(t, err)
+		require.Equal(t, []string{"README.md", "CHANGELOG.md"}, changes)
+	}
+
+	t.Log("the operations are recorded, the repository is not changed")
+	{
+		require.NoError(t, repo.Add([]string{"README.md", "CHANGELOG.md"}))
+		require.NoError(t, repo.Commit("v1.0.0", CommitOptions{}))
+		require.NoError(t, repo.CheckoutBranch("release"))
+		require.NoError(t, repo.Merge("master", "Merge master into release, release: v1.0.0", CommitOptions{}))
+		require.NoError(t, repo.Tag("1.0.0", TagOptions{Message: "v1.0.0\n\n* feat: initial"}))
+		require.NoError(t, repo.Push("origin", []string{"master", "release"}, []string{"1.0.0"}))
+
+		require.Equal(t, []string{
+			"git add README.md",
+			"git add CHANGELOG.md",
+			`git commit -m "v1.0.0"`,
+			"git checkout release",
+			`git merge master --no-ff -m "Merge master into release, release: v1.0.0"`,
+			`git tag --annotate --cleanup=whitespace -m "v1.0.0" 1.0.0`,
+			"git push --atomic origin master release 1.0.0",
+		}, repo.Operations)
+
+		currentBranch, err := repo.CurrentBranchName()
+		require.NoError(t, err)
+		require.Equal(t, "release", currentBranch)
+
+		head, err := repo.BranchHead("master")
+		require.NoError(t, err)
+		require.Equal(t, initialHash.String(), head)
+
+		tags, err := repo.Tags()
+		require.NoError(t, err)
+		require.Equal(t, 0, len(tags))
+
+		head, err = repo.Repository.CurrentBranchName()
+		require.NoError(t, err)
+		require.Equal(t, "master", head)
+	}
+
+	t.Log("invalid options fail as in a real run")
+	{
+		require.Error(t, repo.Tag("1.0.0", TagOptions{Sign: &SigningOptions{}}))
+	}
+}
@@ -0,0 +1,381 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+//=======================================
+// Model
+//=======================================
+
+// execRepository runs the git binary (found in the PATH) in the current working directory.
+type execRepository struct{}
+
+// NewExecRepository ...
+func NewExecRepository() Repository {
+	return execRepository{}
+}
+
+//=======================================
+// Repository
+//=======================================
+
+// GitDir ...
+func (repo execRepository) GitDir() (string, error) {
+	out, err := NewPrintableCommand("git", "rev-parse", "--absolute-git-dir").Run()
+	if err != nil {
+		return "", err
+	}
+	return Strip(out), nil
+}
+
+// LocalBranches ...
+func (repo execRepository) LocalBranches() ([]string, error) {
+	out, err := NewPrintableCommand("git", "branch", "--list").Run()
+	if err != nil {
+		return []string{}, err
+	}
+	return splitByNewLineAndStrip(out), nil
+}
+
+// CurrentBranchName ...
+func (repo execRepository) CurrentBranchName() (string, error) {
+	out, err := NewPrintableCommand("git", "symbolic-ref", "--short", "HEAD").Run()
+	if err != nil {
+		return "", err
+	}
+	return Strip(out), nil
+
+}
+
+// AreUncommitedChanges ...
+func (repo execRepository) AreUncommitedChanges() (bool, error) {
+	out, err := NewPrintableCommand("git", "status", "--porcelain").Run()
+	if err != nil {
+		return false, err
+	}
+	return (out != ""), nil
+}
+
+// GetChangedFiles ...
+func (repo execRepository) GetChangedFiles() ([]string, error) {
+	out, err := NewPrintableCommand("git", "status", "--porcelain").Run()
+	if err != nil {
+		return []string{}, err
+	}
+
+	changes := []string{}
+	changeList := splitByNewLineAndStrip(out)
+	for _, change := range changeList {
+		changeSplits := strings.Split(change, " ")
+
+		normalizedChangeSplits := changeSplits[1:len(changeSplits)]
+		normalizedChangeStr := strings.Join(normalizedChangeSplits, " ")
+
+		changes = append(changes, normalizedChangeStr)
+	}
+
+	return changes, nil
+}
+
+// CheckoutBranch ...
+func (repo execRepository) CheckoutBranch(branch string) error {
+	if _, err := NewPrintableCommand("git", "checkout", branch).Run(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// BranchHead ...
+func (repo execRepository) BranchHead(branch string) (string, error) {
+	out, err := NewPrintableCommand("git", "rev-parse", "--verify", "refs/heads/"+branch).Run()
+	if err != nil {
+		return "", err
+	}
+	return Strip(out), nil
+}
+
+// ResetBranch ...
+func (repo execRepository) ResetBranch(branch, hash string) error {
+	currentBranch, err := repo.CurrentBranchName()
+	if err != nil {
+		return err
+	}
+
+	if currentBranch == branch {
+		_, err = NewPrintableCommand("git", "reset", "--hard", hash).Run()
+	} else {
+		_, err = NewPrintableCommand("git", "branch", "--force", branch, hash).Run()
+	}
+	return err
+}
+
+// Tags ...
+func (repo execRepository) Tags() ([]string, error) {
+	out, err := NewPrintableCommand("git", "tag", "--list").Run()
+	if err != nil {
+		return []string{}, err
+	}
+	return splitByNewLineAndStrip(out), nil
+}
+
+// DeleteTag ...
+func (repo execRepository) DeleteTag(tag string) error {
+	if _, err := NewPrintableCommand("git", "tag", "--delete", tag).Run(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Remotes ...
+func (repo execRepository) Remotes() ([]string, error) {
+	out, err := NewPrintableCommand("git", "remote").Run()
+	if err != nil {
+		return []string{}, err
+	}
+	return splitByNewLineAndStrip(out), nil
+}
+
+// RemoteBranchesContaining ...
+func (repo execRepository) RemoteBranchesContaining(hash string) ([]string, error) {
+	out, err := NewPrintableCommand("git", "branch", "--remotes", "--contains", hash, "--format=%(refname:short)").Run()
+	if err != nil {
+		return []string{}, err
+	}
+	return splitByNewLineAndStrip(out), nil
+}
+
+// RemoteTags ...
+func (repo execRepository) RemoteTags(remote string) ([]string, error) {
+	out, err := NewPrintableCommand("git", "ls-remote", "--tags", "--refs", remote).Run()
+	if err != nil {
+		return []string{}, err
+	}
+
+	tags := []string{}
+	for _, line := range splitByNewLineAndStrip(out) {
+		// <hash>	refs/tags/<tag>
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		tags = append(tags, strings.TrimPrefix(fields[1], "refs/tags/"))
+	}
+	return tags, nil
+}
+
+// RemoteBranchHead ...
+func (repo execRepository) RemoteBranchHead(remote, branch string) (string, error) {
+	out, err := NewPrintableCommand("git", "ls-remote", "--heads", remote, "refs/heads/"+branch).Run()
+	if err != nil {
+		return "", err
+	}
+
+	for _, line := range splitByNewLineAndStrip(out) {
+		// <hash>	refs/heads/<branch>
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[1] == "refs/heads/"+branch {
+			return fields[0], nil
+		}
+	}
+	return "", nil
+}
+
+// Push ...
+func (repo execRepository) Push(remote string, branches, tags []string) error {
+	args := []string{"git", "push", "--atomic", remote}
+	for _, branch := range branches {
+		args = append(args, fmt.Sprintf("refs/heads/%s:refs/heads/%s", branch, branch))
+	}
+	for _, tag := range tags {
+		args = append(args, fmt.Sprintf("refs/tags/%s:refs/tags/%s", tag, tag))
+	}
+
+	if _, err := NewPrintableCommand(args...).Run(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// FirstCommit ...
+func (repo execRepository) FirstCommit() (CommitModel, error) {
+	out, err := NewPrintableCommand("git", "rev-list", "--max-parents=0", commitFormat, "HEAD").Run()
+	if err != nil {
+		return CommitModel{}, err
+	}
+	commit, err := parseCommit(Strip(out))
+	if err != nil {
+		return CommitModel{}, fmt.Errorf("Failed to parse commit: %#v", err)
+	}
+	return commit, nil
+}
+
+// LatestCommit ...
+func (repo execRepository) LatestCommit() (CommitModel, error) {
+	out, err := NewPrintableCommand("git", "log", "-1", commitFormat).Run()
+	if err != nil {
+		return CommitModel{}, err
+	}
+	commit, err := parseCommit(Strip(out))
+	if err != nil {
+		return CommitModel{}, fmt.Errorf("Failed to parse commit: %#v", err)
+	}
+	return commit, nil
+}
+
+// CommitOf ...
+func (repo execRepository) CommitOf(revision string) (CommitModel, error) {
+	out, err := NewPrintableCommand("git", "rev-list", "-n", "1", commitFormat, revision).Run()
+	if err != nil {
+		return CommitModel{}, err
+	}
+	commit, err := parseCommit(Strip(out))
+	if err != nil {
+		return CommitModel{}, fmt.Errorf("Failed to parse commit: %#v", err)
+	}
+	return commit, nil
+}
+
+// CommitOfTag ...
+func (repo execRepository) CommitOfTag(tag string) (CommitModel, error) {
+	commit, err := repo.CommitOf(tag)
+	if err != nil {
+		return CommitModel{}, err
+	}
+	commit.Tag = tag
+	return commit, nil
+}
+
+// GetCommitsFrom returns the commits reachable from HEAD but not from startCommitPtr,
+// in git's topological order (newest first). A nil startCommitPtr means the whole history.
+func (repo execRepository) GetCommitsFrom(startCommitPtr *CommitModel) ([]CommitModel, error) {
+	return repo.getCommits(startCommitPtr, "HEAD")
+}
+
+// GetCommitsBetween returns the commits reachable from endCommit but not from startCommitPtr,
+// in git's topological order (newest first). A nil startCommitPtr means the whole history of endCommit.
+func (repo execRepository) GetCommitsBetween(startCommitPtr *CommitModel, endCommit CommitModel) ([]CommitModel, error) {
+	return repo.getCommits(startCommitPtr, endCommit.Hash)
+}
+
+func (repo execRepository) getCommits(startCommitPtr *CommitModel, endRevision string) ([]CommitModel, error) {
+	revisionRange := endRevision
+	if startCommitPtr != nil {
+		revisionRange = fmt.Sprintf("%s..%s", startCommitPtr.Hash, endRevision)
+	}
+
+	log.Debug("")
+	log.Debugf("GetCommits: %v (%s)\n", startCommitPtr, revisionRange)
+
+	out, err := NewPrintableCommand("git", "-c", "core.quotePath=false", "log", "--topo-order", "--name-only", commitWithFilesFormat, revisionRange).Run()
+	if err != nil {
+		return []CommitModel{}, err
+	}
+
+	commits, err := parseCommitList(out)
+	if err != nil {
+		return []CommitModel{}, err
+	}
+
+	for _, commit := range commits {
+		log.Debugf("commit: %v\n", commit)
+	}
+
+	log.Debug("")
+
+	return commits, nil
+}
+
+// IsAncestor ...
+func (repo execRepository) IsAncestor(ancestor, descendant string) (bool, error) {
+	if _, err := NewPrintableCommand("git", "cat-file", "-e", ancestor+"^{commit}").Run(); err != nil {
+		return false, nil
+	}
+
+	_, err := NewPrintableCommand("git", "merge-base", "--is-ancestor", ancestor, descendant).Run()
+	if err == nil {
+		return true, nil
+	}
+	if cmdErr, ok := err.(*CommandError); ok && cmdErr.ExitCode == 1 {
+		return false, nil
+	}
+	return false, err
+}
+
+// Add ...
+func (repo execRepository) Add(files []string) error {
+	for _, file := range files {
+		if _, err := NewPrintableCommand("git", "add", file).Run(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// gitCommand returns the git command, with the signing configuration if the commit has to be signed.
+func (options CommitOptions) gitCommand(command string, args ...string) ([]string, error) {
+	commandParts := []string{"git"}
+	if options.Sign != nil {
+		if err := options.Sign.Validate(); err != nil {
+			return []string{}, err
+		}
+		commandParts = append(commandParts, options.Sign.gitConfigArgs()...)
+		args = append([]string{"--gpg-sign"}, args...)
+	}
+	commandParts = append(commandParts, command)
+	return append(commandParts, args...), nil
+}
+
+// Commit ...
+func (repo execRepository) Commit(message string, options CommitOptions) error {
+	commandParts, err := options.gitCommand("commit", "-m", message)
+	if err != nil {
+		return err
+	}
+	if _, err := NewPrintableCommand(commandParts...).Run(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Merge ...
+func (repo execRepository) Merge(branch, commitMessage string, options CommitOptions) error {
+	commandParts, err := options.gitCommand("merge", branch, "--no-ff", "-m", commitMessage)
+	if err != nil {
+		return err
+	}
+	if _, err := NewPrintableCommand(commandParts...).Run(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Tag ...
+func (repo execRepository) Tag(name string, options TagOptions) error {
+	if err := options.Validate(); err != nil {
+		return err
+	}
+
+	commandParts := []string{"git"}
+	if options.Sign != nil {
+		commandParts = append(commandParts, options.Sign.gitConfigArgs()...)
+	}
+	commandParts = append(commandParts, "tag")
+	if options.Message != "" {
+		if options.Sign != nil {
+			commandParts = append(commandParts, "--sign")
+		} else {
+			commandParts = append(commandParts, "--annotate")
+		}
+		commandParts = append(commandParts, "--cleanup=whitespace", "-m", options.Message)
+	}
+	commandParts = append(commandParts, name)
+
+	if _, err := NewPrintableCommand(commandParts...).Run(); err != nil {
+		return err
+	}
+	return nil
+}
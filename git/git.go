@@ -7,8 +7,7 @@ import (
 	"strings"
 	"time"
 
-	log "github.com/Sirupsen/logrus"
-	version "github.com/hashicorp/go-version"
+	"github.com/bitrise-tools/releaseman/versioning"
 )
 
 const (
@@ -16,6 +15,17 @@ const (
 	datePrefix    = "date: "
 	authorPrefix  = "author: "
 	messagePrefix = "message: "
+	// root commits have no parents, and the trailing space gets stripped
+	parentsPrefix = "parents:"
+	// body lines are indented, so they can not be mistaken for the fields above
+	bodyPrefix = "body:"
+	bodyIndent = "    "
+	// the changed files are listed after this line by 'git log --name-only'
+	filesPrefix = "files:"
+
+	commitFormat = `--pretty=format:commit: %H%ndate: %ct%nauthor: %an%nparents: %P%nmessage: %s%nbody:%n%w(0,4,4)%b`
+	// commitWithFilesFormat has to be used with --name-only
+	commitWithFilesFormat = commitFormat + `%w(0,0,0)%nfiles:`
 )
 
 //=======================================
@@ -29,6 +39,21 @@ type CommitModel struct {
 	Date    time.Time
 	Author  string
 	Tag     string
+	// Version is the version parsed from the Tag
+	Version string
+	Parents []string
+	Body    string
+	// Files changed by the commit (compared to its parent), empty for merge commits.
+	// It is set by GetCommitsFrom and GetCommitsBetween only, the component paths and the changelog path filters are matched against it.
+	Files []string
+
+	// Conventional Commits fields (https://www.conventionalcommits.org),
+	// Description falls back to the Message for not conventional commits.
+	Type        string
+	Scope       string
+	Breaking    bool
+	Description string
+	Footers     []CommitFooterModel
 }
 
 //=======================================
@@ -43,6 +68,19 @@ func SortByDate(commits []CommitModel) {
 	sortBy(byDate).sort(commits)
 }
 
+// SortByVersion sorts the commits by the version precedence of their Version in the scheme,
+// commits with the same (or invalid) version are sorted by date.
+func SortByVersion(commits []CommitModel, scheme versioning.Scheme) {
+	byVersion := func(c1, c2 *CommitModel) bool {
+		if cmp, err := scheme.Compare(c1.Version, c2.Version); err == nil && cmp != 0 {
+			return cmp < 0
+		}
+		return c1.Date.Before(c2.Date)
+	}
+
+	sortBy(byVersion).sort(commits)
+}
+
 type sortBy func(p1, p2 *CommitModel) bool
 
 func (by sortBy) sort(commits []CommitModel) {
@@ -124,6 +162,15 @@ func parseCommitList(commitListStr string) ([]CommitModel, error) {
 		}
 	}
 
+	if commitLine != "" {
+		commit, err := parseCommit(commitLine)
+		if err != nil {
+			return []CommitModel{}, err
+		}
+
+		commits = append(commits, commit)
+	}
+
 	return commits, nil
 }
 
@@ -138,12 +185,26 @@ func parseCommit(commitLineStr string) (CommitModel, error) {
 		commit: 7d3243a6e91aa46f28ed3811bb4bc26a05ce0b02
 		date: 1455788198
 		author: Krisztián Gödrei
+		parents: 0b5c9a5f3a8b2e0a4bd6fb1b1b1bd2e03c0f4f1d
 		message: FIX: parsing git commits
 	*/
 	hash := ""
 	dateStr := ""
 	author := ""
 	message := ""
+	parents := []string{}
+
+	var files []string
+	if filesIdx := strings.Index(commitLineStr, "\n"+filesPrefix); filesIdx != -1 {
+		files = parseFiles(commitLineStr[filesIdx+len(filesPrefix)+1:])
+		commitLineStr = commitLineStr[:filesIdx]
+	}
+
+	body := ""
+	if bodyIdx := strings.Index(commitLineStr, "\n"+bodyPrefix); bodyIdx != -1 {
+		body = parseBody(commitLineStr[bodyIdx+len(bodyPrefix)+1:])
+		commitLineStr = commitLineStr[:bodyIdx]
+	}
 
 	commitSplits := splitByNewLineAndStrip(commitLineStr)
 	if len(commitSplits) < 4 {
@@ -158,6 +219,8 @@ func parseCommit(commitLineStr string) (CommitModel, error) {
 			dateStr = strings.TrimPrefix(line, datePrefix)
 		} else if strings.HasPrefix(line, authorPrefix) {
 			author = strings.TrimPrefix(line, authorPrefix)
+		} else if !messageStart && strings.HasPrefix(line, parentsPrefix) {
+			parents = strings.Fields(strings.TrimPrefix(line, parentsPrefix))
 		} else if strings.HasPrefix(line, messagePrefix) {
 			messageStart = true
 		}
@@ -180,221 +243,245 @@ func parseCommit(commitLineStr string) (CommitModel, error) {
 		return CommitModel{}, err
 	}
 
-	return CommitModel{
+	commit := CommitModel{
 		Hash:    hash,
 		Message: message,
 		Date:    date,
 		Author:  author,
-	}, nil
+		Parents: parents,
+		Body:    body,
+		Files:   files,
+	}
+
+	return parseConventionalCommit(commit), nil
+}
+
+func parseFiles(filesStr string) []string {
+	files := []string{}
+	for _, line := range strings.Split(filesStr, "\n") {
+		if line != "" {
+			files = append(files, line)
+		}
+	}
+	return files
+}
+
+func parseBody(bodyStr string) string {
+	lines := []string{}
+	for _, line := range strings.Split(bodyStr, "\n") {
+		if line == "" || line == bodyIndent {
+			lines = append(lines, "")
+			continue
+		}
+		if !strings.HasPrefix(line, bodyIndent) {
+			// not part of the body, e.g. the next 'commit <hash>' line of 'git rev-list'
+			break
+		}
+		lines = append(lines, strings.TrimRight(strings.TrimPrefix(line, bodyIndent), " \t\r"))
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
 }
 
 //=======================================
 // Git functions
 //=======================================
 
+// GitDir ...
+func GitDir() (string, error) {
+	return repository.GitDir()
+}
+
 // LocalBranches ...
 func LocalBranches() ([]string, error) {
-	out, err := NewPrintableCommand("git", "branch", "--list").Run()
-	if err != nil {
-		return []string{}, err
-	}
-	return splitByNewLineAndStrip(out), nil
+	return repository.LocalBranches()
 }
 
-// VersionTaggedCommits ...
-func VersionTaggedCommits() ([]CommitModel, error) {
-	out, err := NewPrintableCommand("git", "tag", "--list").Run()
+// VersionTaggedCommits returns the commits of the tags matching the tag format and the version scheme, sorted by version.
+// The version of the tag is set in the Version field of the commit.
+func VersionTaggedCommits(format TagFormat, scheme versioning.Scheme) ([]CommitModel, error) {
+	tags, err := repository.Tags()
 	if err != nil {
 		return []CommitModel{}, err
 	}
+
 	taggedCommits := []CommitModel{}
-	tags := splitByNewLineAndStrip(out)
 	for _, tag := range tags {
-		// is tag sem-ver tag?
-		_, err := version.NewVersion(tag)
-		if err != nil {
+		version, ok := format.Version(tag, scheme)
+		if !ok {
 			continue
 		}
 
-		out, err = NewPrintableCommand("git", "rev-list", "-n", "1", `--pretty=format:commit: %H%ndate: %ct%nauthor: %an%nmessage: %s`, tag).Run()
+		commit, err := repository.CommitOfTag(tag)
 		if err != nil {
 			return []CommitModel{}, err
 		}
-
-		commit, err := parseCommit(Strip(out))
-		if err != nil {
-			return []CommitModel{}, fmt.Errorf("Failed to parse commit: %#v", err)
-		}
-		commit.Tag = tag
+		commit.Version = version
 
 		taggedCommits = append(taggedCommits, commit)
 	}
 
-	SortByDate(taggedCommits)
+	SortByVersion(taggedCommits, scheme)
 
 	return taggedCommits, nil
 }
 
-// CurrentBranchName ...
-func CurrentBranchName() (string, error) {
-	out, err := NewPrintableCommand("git", "symbolic-ref", "--short", "HEAD").Run()
+// BranchVersionTaggedCommits returns the version tagged commits reachable from any of the branches, sorted by version.
+// The last one is the current version of the branches, tags of other maintenance lines are left out.
+// Pass both the development and the release branch: the release tag is on the merge commit of the release branch,
+// which is not reachable from the development branch. Empty branch names are skipped.
+func BranchVersionTaggedCommits(format TagFormat, scheme versioning.Scheme, branches ...string) ([]CommitModel, error) {
+	taggedCommits, err := VersionTaggedCommits(format, scheme)
 	if err != nil {
-		return "", err
+		return []CommitModel{}, err
+	}
+
+	heads := []string{}
+	for _, branch := range branches {
+		if branch == "" {
+			continue
+		}
+		head, err := repository.BranchHead(branch)
+		if err != nil {
+			return []CommitModel{}, err
+		}
+		heads = append(heads, head)
+	}
+
+	branchTaggedCommits := []CommitModel{}
+	for _, taggedCommit := range taggedCommits {
+		for _, head := range heads {
+			reachable, err := repository.IsAncestor(taggedCommit.Hash, head)
+			if err != nil {
+				return []CommitModel{}, err
+			}
+			if reachable {
+				branchTaggedCommits = append(branchTaggedCommits, taggedCommit)
+				break
+			}
+		}
 	}
-	return Strip(out), nil
 
+	return branchTaggedCommits, nil
+}
+
+// CurrentBranchName ...
+func CurrentBranchName() (string, error) {
+	return repository.CurrentBranchName()
 }
 
 // AreUncommitedChanges ...
 func AreUncommitedChanges() (bool, error) {
-	out, err := NewPrintableCommand("git", "status", "--porcelain").Run()
-	if err != nil {
-		return false, err
-	}
-	return (out != ""), nil
+	return repository.AreUncommitedChanges()
 }
 
 // GetChangedFiles ...
 func GetChangedFiles() ([]string, error) {
-	out, err := NewPrintableCommand("git", "status", "--porcelain").Run()
-	if err != nil {
-		return []string{}, err
-	}
+	return repository.GetChangedFiles()
+}
 
-	changes := []string{}
-	changeList := splitByNewLineAndStrip(out)
-	for _, change := range changeList {
-		changeSplits := strings.Split(change, " ")
+// CheckoutBranch ...
+func CheckoutBranch(branch string) error {
+	return repository.CheckoutBranch(branch)
+}
 
-		normalizedChangeSplits := changeSplits[1:len(changeSplits)]
-		normalizedChangeStr := strings.Join(normalizedChangeSplits, " ")
+// BranchHead ...
+func BranchHead(branch string) (string, error) {
+	return repository.BranchHead(branch)
+}
 
-		changes = append(changes, normalizedChangeStr)
-	}
+// ResetBranch ...
+func ResetBranch(branch, hash string) error {
+	return repository.ResetBranch(branch, hash)
+}
 
-	return changes, nil
+// Tags ...
+func Tags() ([]string, error) {
+	return repository.Tags()
 }
 
-// CheckoutBranch ...
-func CheckoutBranch(branch string) error {
-	if _, err := NewPrintableCommand("git", "checkout", branch).Run(); err != nil {
-		return err
-	}
-	return nil
+// DeleteTag ...
+func DeleteTag(tag string) error {
+	return repository.DeleteTag(tag)
+}
+
+// Remotes ...
+func Remotes() ([]string, error) {
+	return repository.Remotes()
+}
+
+// RemoteBranchesContaining ...
+func RemoteBranchesContaining(hash string) ([]string, error) {
+	return repository.RemoteBranchesContaining(hash)
+}
+
+// RemoteTags ...
+func RemoteTags(remote string) ([]string, error) {
+	return repository.RemoteTags(remote)
+}
+
+// RemoteBranchHead ...
+func RemoteBranchHead(remote, branch string) (string, error) {
+	return repository.RemoteBranchHead(remote, branch)
+}
+
+// Push ...
+func Push(remote string, branches, tags []string) error {
+	return repository.Push(remote, branches, tags)
 }
 
 // FirstCommit ...
 func FirstCommit() (CommitModel, error) {
-	out, err := NewPrintableCommand("git", "rev-list", "--max-parents=0", `--pretty=format:commit: %H%ndate: %ct%nauthor: %an%nmessage: %s`, "HEAD").Run()
-	if err != nil {
-		return CommitModel{}, err
-	}
-	commit, err := parseCommit(Strip(out))
-	if err != nil {
-		return CommitModel{}, fmt.Errorf("Failed to parse commit: %#v", err)
-	}
-	return commit, nil
+	return repository.FirstCommit()
 }
 
 // LatestCommit ...
 func LatestCommit() (CommitModel, error) {
-	out, err := NewPrintableCommand("git", "log", "-1", `--pretty=format:commit: %H%ndate: %ct%nauthor: %an%nmessage: %s`).Run()
-	if err != nil {
-		return CommitModel{}, err
-	}
-	commit, err := parseCommit(Strip(out))
-	if err != nil {
-		return CommitModel{}, fmt.Errorf("Failed to parse commit: %#v", err)
-	}
-	return commit, nil
+	return repository.LatestCommit()
+}
+
+// CommitOf returns the commit of the revision, e.g. a hash, a tag or a branch.
+func CommitOf(revision string) (CommitModel, error) {
+	return repository.CommitOf(revision)
 }
 
 // CommitOfTag ...
 func CommitOfTag(tag string) (CommitModel, error) {
-	out, err := NewPrintableCommand("git", "show-ref", tag).Run()
-	if err != nil {
-		return CommitModel{}, err
-	}
-	commit, err := parseCommit(out)
-	if err != nil {
-		return CommitModel{}, fmt.Errorf("Failed to parse commit: %#v", err)
-	}
-	return commit, nil
+	return repository.CommitOfTag(tag)
 }
 
-// GetCommitsFrom ...
+// GetCommitsFrom returns the commits reachable from HEAD but not from startCommitPtr,
+// in git's topological order (newest first). A nil startCommitPtr means the whole history.
 func GetCommitsFrom(startCommitPtr *CommitModel) ([]CommitModel, error) {
-	out, err := NewPrintableCommand("git", "log", `--pretty=format:commit: %H%ndate: %ct%nauthor: %an%nmessage: %s`).Run()
-	if err != nil {
-		return []CommitModel{}, err
-	}
-
-	commits, err := parseCommitList(out)
-	if err != nil {
-		return []CommitModel{}, err
-	}
-
-	SortByDate(commits)
-
-	relevantCommits := []CommitModel{}
-	isRelevantCommit := false
-
-	log.Debug("")
-	log.Debugf("GetCommitsFrom: %v\n", startCommitPtr)
-
-	for _, commit := range commits {
-		if !isRelevantCommit && startCommitPtr == nil {
-			log.Debug("===> isRelevantCommit = true -- No start commit")
-			isRelevantCommit = true
-		}
-
-		if !isRelevantCommit && startCommitPtr != nil && (*startCommitPtr).Date.Sub(commit.Date) < 0 {
-			log.Debug("===> isRelevantCommit = true")
-			isRelevantCommit = true
-		}
-
-		if isRelevantCommit {
-			relevantCommits = append(relevantCommits, commit)
-		}
-
-		log.Debugf("commit: %v\n", commit)
-	}
+	return repository.GetCommitsFrom(startCommitPtr)
+}
 
-	log.Debug("")
+// GetCommitsBetween returns the commits reachable from endCommit but not from startCommitPtr,
+// in git's topological order (newest first). A nil startCommitPtr means the whole history of endCommit.
+func GetCommitsBetween(startCommitPtr *CommitModel, endCommit CommitModel) ([]CommitModel, error) {
+	return repository.GetCommitsBetween(startCommitPtr, endCommit)
+}
 
-	return relevantCommits, nil
+// IsAncestor ...
+func IsAncestor(ancestor, descendant string) (bool, error) {
+	return repository.IsAncestor(ancestor, descendant)
 }
 
 // Add ...
 func Add(files []string) error {
-	for _, file := range files {
-		if _, err := NewPrintableCommand("git", "add", file).Run(); err != nil {
-			return err
-		}
-	}
-	return nil
+	return repository.Add(files)
 }
 
 // Commit ...
-func Commit(message string) error {
-	if _, err := NewPrintableCommand("git", "commit", "-m", message).Run(); err != nil {
-		return err
-	}
-	return nil
+func Commit(message string, options CommitOptions) error {
+	return repository.Commit(message, options)
 }
 
 // Merge ...
-func Merge(branch, commitMessage string) error {
-	if _, err := NewPrintableCommand("git", "merge", branch, "--no-ff", "-m", commitMessage).Run(); err != nil {
-		return err
-	}
-	return nil
+func Merge(branch, commitMessage string, options CommitOptions) error {
+	return repository.Merge(branch, commitMessage, options)
 }
 
 // Tag ...
-func Tag(version string) error {
-	if _, err := NewPrintableCommand("git", "tag", version).Run(); err != nil {
-		return err
-	}
-	return nil
+func Tag(name string, options TagOptions) error {
+	return repository.Tag(name, options)
 }
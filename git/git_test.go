@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/bitrise-tools/releaseman/versioning"
 	"github.com/hashicorp/go-version"
 	"github.com/stretchr/testify/require"
 )
@@ -28,6 +29,25 @@ func TestVersionTaggedCommits(t *testing.T) {
 	}
 }
 
+func TestSortByVersion(t *testing.T) {
+	date := time.Unix(1454498663, 0)
+	commits := []CommitModel{
+		{Version: "2.0.0", Date: date},
+		{Version: "1.10.0", Date: date.Add(time.Hour)},
+		{Version: "1.4.3", Date: date.Add(2 * time.Hour)},
+		{Version: "2.0.0-rc.1", Date: date.Add(-time.Hour)},
+		{Version: "1.9", Date: date},
+	}
+
+	SortByVersion(commits, versioning.SemVer{})
+
+	versions := []string{}
+	for _, commit := range commits {
+		versions = append(versions, commit.Version)
+	}
+	require.Equal(t, []string{"1.4.3", "1.9", "1.10.0", "2.0.0-rc.1", "2.0.0"}, versions)
+}
+
 func TestParseDate(t *testing.T) {
 	unixTimestampStr := "1454498673"
 	unixTime, err := parseDate(unixTimestampStr)
@@ -124,6 +144,35 @@ message: `
 		require.Equal(t, "Krisztián Gödrei", commit.Author)
 	}
 
+	t.Log("Test commit with parents")
+	{
+		commitLine := `commit 85d8658733f73ae6d5407e8e4c2b81a5f2ed016c
+commit: 85d8658733f73ae6d5407e8e4c2b81a5f2ed016c
+date: 1455631980
+author: Krisztián Gödrei
+parents: 7d3243a6e91aa46f28ed3811bb4bc26a05ce0b02 b738dee2d32def019a4d553249004364046dc1bd
+message: Merge branch 'feature'`
+
+		commit, err := parseCommit(commitLine)
+		require.Equal(t, nil, err)
+		require.Equal(t, []string{"7d3243a6e91aa46f28ed3811bb4bc26a05ce0b02", "b738dee2d32def019a4d553249004364046dc1bd"}, commit.Parents)
+		require.Equal(t, "Merge branch 'feature'", commit.Message)
+	}
+
+	t.Log("Test root commit without parents")
+	{
+		commitLine := `commit: 85d8658733f73ae6d5407e8e4c2b81a5f2ed016c
+date: 1455631980
+author: Krisztián Gödrei
+parents: 
+message: initial commit`
+
+		commit, err := parseCommit(commitLine)
+		require.Equal(t, nil, err)
+		require.Equal(t, 0, len(commit.Parents))
+		require.Equal(t, "initial commit", commit.Message)
+	}
+
 	t.Log("Test commit with multiline message")
 	{
 		multilineMessage := `multiline
@@ -143,3 +192,61 @@ message: ` + multilineMessage
 		require.Equal(t, multilineMessage, commit.Message)
 	}
 }
+
+func TestParseCommitList(t *testing.T) {
+	commitListStr := `commit: 85d8658733f73ae6d5407e8e4c2b81a5f2ed016c
+date: 1455631990
+author: Krisztián Gödrei
+parents: 7d3243a6e91aa46f28ed3811bb4bc26a05ce0b02
+message: second change
+commit: 7d3243a6e91aa46f28ed3811bb4bc26a05ce0b02
+date: 1455631980
+author: Krisztián Gödrei
+parents: 
+message: first change`
+
+	commits, err := parseCommitList(commitListStr)
+	require.Equal(t, nil, err)
+	require.Equal(t, 2, len(commits))
+	require.Equal(t, "85d8658733f73ae6d5407e8e4c2b81a5f2ed016c", commits[0].Hash)
+	require.Equal(t, []string{"7d3243a6e91aa46f28ed3811bb4bc26a05ce0b02"}, commits[0].Parents)
+	require.Equal(t, "7d3243a6e91aa46f28ed3811bb4bc26a05ce0b02", commits[1].Hash)
+	require.Equal(t, "first change", commits[1].Message)
+
+	commits, err = parseCommitList("")
+	require.Equal(t, nil, err)
+	require.Equal(t, 0, len(commits))
+
+	t.Log("with changed files (git log --name-only)")
+	{
+		commitListStr := `commit: 85d8658733f73ae6d5407e8e4c2b81a5f2ed016c
+date: 1455631990
+author: Krisztián Gödrei
+parents: 7d3243a6e91aa46f28ed3811bb4bc26a05ce0b02
+message: feat: mobile change
+body:
+    files: in the body
+
+files:
+mobile/app.go
+mobile/README.md
+
+commit: 7d3243a6e91aa46f28ed3811bb4bc26a05ce0b02
+date: 1455631980
+author: Krisztián Gödrei
+parents: 
+message: first change
+body:
+
+files:
+`
+
+		commits, err := parseCommitList(commitListStr)
+		require.Equal(t, nil, err)
+		require.Equal(t, 2, len(commits))
+		require.Equal(t, "files: in the body", commits[0].Body)
+		require.Equal(t, []string{"mobile/app.go", "mobile/README.md"}, commits[0].Files)
+		require.Equal(t, "first change", commits[1].Message)
+		require.Equal(t, []string{}, commits[1].Files)
+	}
+}
@@ -0,0 +1,161 @@
+package git
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+)
+
+//=======================================
+// Utility
+//=======================================
+
+// flattenTree lists the files (and submodules) of the tree by their path.
+func flattenTree(tree *object.Tree) (map[string]object.TreeEntry, error) {
+	entries := map[string]object.TreeEntry{}
+
+	walker := object.NewTreeWalker(tree, true, nil)
+	defer walker.Close()
+	for {
+		name, entry, err := walker.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if entry.Mode == filemode.Dir {
+			continue
+		}
+		entries[name] = entry
+	}
+	return entries, nil
+}
+
+func commitFiles(commit *object.Commit) (map[string]object.TreeEntry, error) {
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, err
+	}
+	return flattenTree(tree)
+}
+
+func sameTreeEntry(entry object.TreeEntry, ok bool, other object.TreeEntry, otherOk bool) bool {
+	if !ok || !otherOk {
+		return ok == otherOk
+	}
+	return entry.Hash == other.Hash && entry.Mode == other.Mode
+}
+
+// mergeFiles merges the changes of both sides since the base file by file, like git's three-way merge,
+// but the contents are not merged: a file changed differently on both sides is a conflict.
+func mergeFiles(base, ours, theirs map[string]object.TreeEntry) (map[string]object.TreeEntry, []string) {
+	paths := map[string]bool{}
+	for _, files := range []map[string]object.TreeEntry{base, ours, theirs} {
+		for pth := range files {
+			paths[pth] = true
+		}
+	}
+
+	merged := map[string]object.TreeEntry{}
+	conflicts := []string{}
+	for pth := range paths {
+		baseEntry, inBase := base[pth]
+		ourEntry, inOurs := ours[pth]
+		theirEntry, inTheirs := theirs[pth]
+
+		entry, ok := ourEntry, inOurs
+		switch {
+		case sameTreeEntry(ourEntry, inOurs, theirEntry, inTheirs):
+		case sameTreeEntry(baseEntry, inBase, ourEntry, inOurs):
+			entry, ok = theirEntry, inTheirs
+		case sameTreeEntry(baseEntry, inBase, theirEntry, inTheirs):
+		default:
+			conflicts = append(conflicts, pth)
+		}
+
+		if ok {
+			merged[pth] = entry
+		}
+	}
+
+	sort.Strings(conflicts)
+	return merged, conflicts
+}
+
+// writeTree stores the tree (and the subtrees) of the files, listed by their path.
+func writeTree(objectStorer storer.EncodedObjectStorer, files map[string]object.TreeEntry) (plumbing.Hash, error) {
+	entries := []object.TreeEntry{}
+	dirs := map[string]map[string]object.TreeEntry{}
+	for pth, entry := range files {
+		if idx := strings.Index(pth, "/"); idx != -1 {
+			dir := pth[:idx]
+			if dirs[dir] == nil {
+				dirs[dir] = map[string]object.TreeEntry{}
+			}
+			dirs[dir][pth[idx+1:]] = entry
+			continue
+		}
+
+		entry.Name = pth
+		entries = append(entries, entry)
+	}
+
+	for dir, dirFiles := range dirs {
+		hash, err := writeTree(objectStorer, dirFiles)
+		if err != nil {
+			return plumbing.ZeroHash, err
+		}
+		entries = append(entries, object.TreeEntry{Name: dir, Mode: filemode.Dir, Hash: hash})
+	}
+
+	// git orders the entries by name, the directory names compared with a trailing slash
+	sortKey := func(entry object.TreeEntry) string {
+		if entry.Mode == filemode.Dir {
+			return entry.Name + "/"
+		}
+		return entry.Name
+	}
+	sort.Slice(entries, func(i, j int) bool { return sortKey(entries[i]) < sortKey(entries[j]) })
+
+	obj := objectStorer.NewEncodedObject()
+	if err := (&object.Tree{Entries: entries}).Encode(obj); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	return objectStorer.SetEncodedObject(obj)
+}
+
+// mergeTree returns the tree of the merge commit of the two commits.
+func (repo goGitRepository) mergeTree(headCommit, branchCommit *object.Commit) (plumbing.Hash, error) {
+	bases, err := headCommit.MergeBase(branchCommit)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	if len(bases) == 0 {
+		return plumbing.ZeroHash, fmt.Errorf("the branches have no common ancestor")
+	}
+
+	baseFiles, err := commitFiles(bases[0])
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	ourFiles, err := commitFiles(headCommit)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	theirFiles, err := commitFiles(branchCommit)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	files, conflicts := mergeFiles(baseFiles, ourFiles, theirFiles)
+	if len(conflicts) > 0 {
+		return plumbing.ZeroHash, fmt.Errorf("both branches changed: %s, merge them with the git binary", strings.Join(conflicts, ", "))
+	}
+	return writeTree(repo.repo.Storer, files)
+}
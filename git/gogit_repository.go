@@ -0,0 +1,762 @@
+package git
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+)
+
+//=======================================
+// Model
+//=======================================
+
+// goGitRepository is a pure Go backend, it works without the git binary
+// and on in-memory repositories as well.
+type goGitRepository struct {
+	repo *gogit.Repository
+}
+
+// NewGoGitRepository ...
+func NewGoGitRepository(repo *gogit.Repository) Repository {
+	return goGitRepository{repo: repo}
+}
+
+// OpenGoGitRepository opens the repository which contains the given path.
+func OpenGoGitRepository(pth string) (Repository, error) {
+	repo, err := gogit.PlainOpenWithOptions(pth, &gogit.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open git repository at (%s), error: %s", pth, err)
+	}
+	return NewGoGitRepository(repo), nil
+}
+
+//=======================================
+// Utility
+//=======================================
+
+// splitCommitMessage splits the message the same way as git's %s (subject) and %b (body) placeholders.
+func splitCommitMessage(message string) (string, string) {
+	paragraphs := strings.SplitN(strings.TrimSpace(message), "\n\n", 2)
+
+	subject := strings.Join(strings.Split(paragraphs[0], "\n"), " ")
+
+	body := ""
+	if len(paragraphs) > 1 {
+		lines := []string{}
+		for _, line := range strings.Split(paragraphs[1], "\n") {
+			lines = append(lines, strings.TrimRight(line, " \t\r"))
+		}
+		body = strings.Trim(strings.Join(lines, "\n"), "\n")
+	}
+
+	return subject, body
+}
+
+func commitModel(commit *object.Commit) CommitModel {
+	subject, body := splitCommitMessage(commit.Message)
+
+	parents := []string{}
+	for _, parentHash := range commit.ParentHashes {
+		parents = append(parents, parentHash.String())
+	}
+
+	return parseConventionalCommit(CommitModel{
+		Hash:    commit.Hash.String(),
+		Message: subject,
+		Body:    body,
+		Date:    time.Unix(commit.Committer.When.Unix(), 0),
+		Author:  commit.Author.Name,
+		Parents: parents,
+	})
+}
+
+// changedFiles lists the files changed by the commit, like 'git log --name-only' (merge commits list no files).
+func changedFiles(commit *object.Commit) ([]string, error) {
+	files := []string{}
+	if commit.NumParents() > 1 {
+		return files, nil
+	}
+
+	tree, err := commit.Tree()
+	if err != nil {
+		return []string{}, err
+	}
+
+	if commit.NumParents() == 0 {
+		if err := tree.Files().ForEach(func(file *object.File) error {
+			files = append(files, file.Name)
+			return nil
+		}); err != nil {
+			return []string{}, err
+		}
+		sort.Strings(files)
+		return files, nil
+	}
+
+	parent, err := commit.Parent(0)
+	if err != nil {
+		return []string{}, err
+	}
+	parentTree, err := parent.Tree()
+	if err != nil {
+		return []string{}, err
+	}
+
+	changes, err := object.DiffTree(parentTree, tree)
+	if err != nil {
+		return []string{}, err
+	}
+	for _, change := range changes {
+		name := change.To.Name
+		if name == "" {
+			name = change.From.Name
+		}
+		files = append(files, name)
+	}
+	sort.Strings(files)
+
+	return files, nil
+}
+
+func (repo goGitRepository) headCommit() (*object.Commit, error) {
+	head, err := repo.repo.Head()
+	if err != nil {
+		return nil, err
+	}
+	return repo.repo.CommitObject(head.Hash())
+}
+
+// signature returns the author of the new commits and tags: the user.name and user.email
+// of the repository, the global or the system config, the first one set wins (like git).
+func (repo goGitRepository) signature() (*object.Signature, error) {
+	localConfig, err := repo.repo.Config()
+	if err != nil {
+		return nil, err
+	}
+	configs := []*config.Config{localConfig}
+	for _, scope := range []config.Scope{config.GlobalScope, config.SystemScope} {
+		scopeConfig, err := config.LoadConfig(scope)
+		if err != nil {
+			return nil, err
+		}
+		configs = append(configs, scopeConfig)
+	}
+
+	signature := &object.Signature{When: time.Now()}
+	for _, cfg := range configs {
+		if signature.Name == "" {
+			signature.Name = cfg.User.Name
+		}
+		if signature.Email == "" {
+			signature.Email = cfg.User.Email
+		}
+	}
+	if signature.Name == "" || signature.Email == "" {
+		return nil, fmt.Errorf("Missing git config: user.name and user.email are required")
+	}
+	return signature, nil
+}
+
+//=======================================
+// Repository
+//=======================================
+
+// GitDir ...
+func (repo goGitRepository) GitDir() (string, error) {
+	storage, ok := repo.repo.Storer.(*filesystem.Storage)
+	if !ok {
+		return "", fmt.Errorf("Repository is not stored on the filesystem")
+	}
+	return filepath.Abs(storage.Filesystem().Root())
+}
+
+// LocalBranches lists the branches in the format of 'git branch --list'.
+func (repo goGitRepository) LocalBranches() ([]string, error) {
+	currentBranch, err := repo.CurrentBranchName()
+	if err != nil {
+		currentBranch = ""
+	}
+
+	iter, err := repo.repo.Branches()
+	if err != nil {
+		return []string{}, err
+	}
+
+	branches := []string{}
+	if err := iter.ForEach(func(ref *plumbing.Reference) error {
+		branches = append(branches, ref.Name().Short())
+		return nil
+	}); err != nil {
+		return []string{}, err
+	}
+
+	sort.Strings(branches)
+
+	for idx, branch := range branches {
+		if branch == currentBranch {
+			branches[idx] = "* " + branch
+		}
+	}
+
+	return branches, nil
+}
+
+// CurrentBranchName ...
+func (repo goGitRepository) CurrentBranchName() (string, error) {
+	head, err := repo.repo.Head()
+	if err != nil {
+		return "", err
+	}
+	if !head.Name().IsBranch() {
+		return "", fmt.Errorf("HEAD (%s) is not a branch", head.Hash())
+	}
+	return head.Name().Short(), nil
+}
+
+// AreUncommitedChanges ...
+func (repo goGitRepository) AreUncommitedChanges() (bool, error) {
+	worktree, err := repo.repo.Worktree()
+	if err != nil {
+		return false, err
+	}
+	status, err := worktree.Status()
+	if err != nil {
+		return false, err
+	}
+	return !status.IsClean(), nil
+}
+
+// GetChangedFiles ...
+func (repo goGitRepository) GetChangedFiles() ([]string, error) {
+	worktree, err := repo.repo.Worktree()
+	if err != nil {
+		return []string{}, err
+	}
+	status, err := worktree.Status()
+	if err != nil {
+		return []string{}, err
+	}
+
+	changes := []string{}
+	for file, fileStatus := range status {
+		if fileStatus.Staging == gogit.Unmodified && fileStatus.Worktree == gogit.Unmodified {
+			continue
+		}
+		changes = append(changes, file)
+	}
+	sort.Strings(changes)
+
+	return changes, nil
+}
+
+// CheckoutBranch ...
+func (repo goGitRepository) CheckoutBranch(branch string) error {
+	worktree, err := repo.repo.Worktree()
+	if err != nil {
+		return err
+	}
+	return worktree.Checkout(&gogit.CheckoutOptions{
+		Branch: plumbing.NewBranchReferenceName(branch),
+	})
+}
+
+// BranchHead ...
+func (repo goGitRepository) BranchHead(branch string) (string, error) {
+	ref, err := repo.repo.Reference(plumbing.NewBranchReferenceName(branch), true)
+	if err != nil {
+		return "", fmt.Errorf("Failed to find branch (%s), error: %s", branch, err)
+	}
+	return ref.Hash().String(), nil
+}
+
+// ResetBranch ...
+func (repo goGitRepository) ResetBranch(branch, hash string) error {
+	currentBranch, err := repo.CurrentBranchName()
+	if err != nil {
+		return err
+	}
+
+	if currentBranch != branch {
+		return repo.repo.Storer.SetReference(plumbing.NewHashReference(plumbing.NewBranchReferenceName(branch), plumbing.NewHash(hash)))
+	}
+
+	worktree, err := repo.repo.Worktree()
+	if err != nil {
+		return err
+	}
+	return worktree.Reset(&gogit.ResetOptions{
+		Commit: plumbing.NewHash(hash),
+		Mode:   gogit.HardReset,
+	})
+}
+
+// Tags ...
+func (repo goGitRepository) Tags() ([]string, error) {
+	iter, err := repo.repo.Tags()
+	if err != nil {
+		return []string{}, err
+	}
+
+	tags := []string{}
+	if err := iter.ForEach(func(ref *plumbing.Reference) error {
+		tags = append(tags, ref.Name().Short())
+		return nil
+	}); err != nil {
+		return []string{}, err
+	}
+	sort.Strings(tags)
+
+	return tags, nil
+}
+
+// DeleteTag ...
+func (repo goGitRepository) DeleteTag(tag string) error {
+	return repo.repo.DeleteTag(tag)
+}
+
+// Remotes ...
+func (repo goGitRepository) Remotes() ([]string, error) {
+	remotes, err := repo.repo.Remotes()
+	if err != nil {
+		return []string{}, err
+	}
+
+	names := []string{}
+	for _, remote := range remotes {
+		names = append(names, remote.Config().Name)
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// RemoteBranchesContaining ...
+func (repo goGitRepository) RemoteBranchesContaining(hash string) ([]string, error) {
+	commit, err := repo.repo.CommitObject(plumbing.NewHash(hash))
+	if err != nil {
+		return []string{}, err
+	}
+
+	iter, err := repo.repo.References()
+	if err != nil {
+		return []string{}, err
+	}
+
+	branches := []string{}
+	if err := iter.ForEach(func(ref *plumbing.Reference) error {
+		if !ref.Name().IsRemote() || ref.Type() != plumbing.HashReference {
+			return nil
+		}
+		remoteCommit, err := repo.repo.CommitObject(ref.Hash())
+		if err != nil {
+			return err
+		}
+		contains, err := commit.IsAncestor(remoteCommit)
+		if err != nil {
+			return err
+		}
+		if contains {
+			branches = append(branches, ref.Name().Short())
+		}
+		return nil
+	}); err != nil {
+		return []string{}, err
+	}
+	sort.Strings(branches)
+
+	return branches, nil
+}
+
+// RemoteTags ...
+func (repo goGitRepository) RemoteTags(remote string) ([]string, error) {
+	gitRemote, err := repo.repo.Remote(remote)
+	if err != nil {
+		return []string{}, err
+	}
+	refs, err := gitRemote.List(&gogit.ListOptions{})
+	if err != nil {
+		return []string{}, err
+	}
+
+	tags := []string{}
+	for _, ref := range refs {
+		if ref.Name().IsTag() {
+			tags = append(tags, ref.Name().Short())
+		}
+	}
+	sort.Strings(tags)
+
+	return tags, nil
+}
+
+// RemoteBranchHead ...
+func (repo goGitRepository) RemoteBranchHead(remote, branch string) (string, error) {
+	gitRemote, err := repo.repo.Remote(remote)
+	if err != nil {
+		return "", err
+	}
+	refs, err := gitRemote.List(&gogit.ListOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	for _, ref := range refs {
+		if ref.Name() == plumbing.NewBranchReferenceName(branch) {
+			return ref.Hash().String(), nil
+		}
+	}
+	return "", nil
+}
+
+// Push ...
+func (repo goGitRepository) Push(remote string, branches, tags []string) error {
+	refSpecs := []config.RefSpec{}
+	for _, branch := range branches {
+		refName := plumbing.NewBranchReferenceName(branch)
+		refSpecs = append(refSpecs, config.RefSpec(fmt.Sprintf("%s:%s", refName, refName)))
+	}
+	for _, tag := range tags {
+		refName := plumbing.NewTagReferenceName(tag)
+		refSpecs = append(refSpecs, config.RefSpec(fmt.Sprintf("%s:%s", refName, refName)))
+	}
+
+	err := repo.repo.Push(&gogit.PushOptions{
+		RemoteName: remote,
+		RefSpecs:   refSpecs,
+		Atomic:     true,
+	})
+	if err == gogit.NoErrAlreadyUpToDate {
+		return nil
+	}
+	return err
+}
+
+// FirstCommit ...
+func (repo goGitRepository) FirstCommit() (CommitModel, error) {
+	commits, err := repo.GetCommitsFrom(nil)
+	if err != nil {
+		return CommitModel{}, err
+	}
+	for i := len(commits) - 1; i >= 0; i-- {
+		if len(commits[i].Parents) == 0 {
+			return commits[i], nil
+		}
+	}
+	return CommitModel{}, fmt.Errorf("No root commit found")
+}
+
+// LatestCommit ...
+func (repo goGitRepository) LatestCommit() (CommitModel, error) {
+	commit, err := repo.headCommit()
+	if err != nil {
+		return CommitModel{}, err
+	}
+	return commitModel(commit), nil
+}
+
+// CommitOf ...
+func (repo goGitRepository) CommitOf(revision string) (CommitModel, error) {
+	hash, err := repo.repo.ResolveRevision(plumbing.Revision(revision))
+	if err != nil {
+		return CommitModel{}, fmt.Errorf("Failed to resolve revision (%s), error: %s", revision, err)
+	}
+	commit, err := repo.repo.CommitObject(*hash)
+	if err != nil {
+		return CommitModel{}, err
+	}
+	return commitModel(commit), nil
+}
+
+// CommitOfTag ...
+func (repo goGitRepository) CommitOfTag(tag string) (CommitModel, error) {
+	hash, err := repo.repo.ResolveRevision(plumbing.Revision(plumbing.NewTagReferenceName(tag)))
+	if err != nil {
+		return CommitModel{}, fmt.Errorf("Failed to resolve tag (%s), error: %s", tag, err)
+	}
+	commit, err := repo.repo.CommitObject(*hash)
+	if err != nil {
+		return CommitModel{}, err
+	}
+
+	taggedCommit := commitModel(commit)
+	taggedCommit.Tag = tag
+
+	return taggedCommit, nil
+}
+
+// GetCommitsFrom returns the commits reachable from HEAD but not from startCommitPtr,
+// in topological order (newest first). A nil startCommitPtr means the whole history.
+func (repo goGitRepository) GetCommitsFrom(startCommitPtr *CommitModel) ([]CommitModel, error) {
+	headCommit, err := repo.headCommit()
+	if err != nil {
+		return []CommitModel{}, err
+	}
+	return repo.getCommits(startCommitPtr, headCommit)
+}
+
+// GetCommitsBetween returns the commits reachable from endCommit but not from startCommitPtr,
+// in topological order (newest first). A nil startCommitPtr means the whole history of endCommit.
+func (repo goGitRepository) GetCommitsBetween(startCommitPtr *CommitModel, endCommit CommitModel) ([]CommitModel, error) {
+	commit, err := repo.repo.CommitObject(plumbing.NewHash(endCommit.Hash))
+	if err != nil {
+		return []CommitModel{}, err
+	}
+	return repo.getCommits(startCommitPtr, commit)
+}
+
+func (repo goGitRepository) getCommits(startCommitPtr *CommitModel, endCommit *object.Commit) ([]CommitModel, error) {
+	excludedHashes := []plumbing.Hash{}
+	if startCommitPtr != nil {
+		startCommit, err := repo.repo.CommitObject(plumbing.NewHash(startCommitPtr.Hash))
+		if err != nil {
+			return []CommitModel{}, err
+		}
+
+		if err := object.NewCommitPreorderIter(startCommit, nil, nil).ForEach(func(commit *object.Commit) error {
+			excludedHashes = append(excludedHashes, commit.Hash)
+			return nil
+		}); err != nil {
+			return []CommitModel{}, err
+		}
+	}
+
+	commitsByHash := map[string]CommitModel{}
+	if err := object.NewCommitPreorderIter(endCommit, nil, excludedHashes).ForEach(func(commit *object.Commit) error {
+		model := commitModel(commit)
+		files, err := changedFiles(commit)
+		if err != nil {
+			return err
+		}
+		model.Files = files
+
+		commitsByHash[commit.Hash.String()] = model
+		return nil
+	}); err != nil {
+		return []CommitModel{}, err
+	}
+
+	return topologicalOrder(endCommit.Hash.String(), commitsByHash), nil
+}
+
+// topologicalOrder lists every commit before its parents (reversed DFS post-order, like 'git log --topo-order').
+func topologicalOrder(headHash string, commitsByHash map[string]CommitModel) []CommitModel {
+	postorderCommits := []CommitModel{}
+	visited := map[string]bool{}
+
+	var visit func(hash string)
+	visit = func(hash string) {
+		commit, ok := commitsByHash[hash]
+		if !ok || visited[hash] {
+			return
+		}
+		visited[hash] = true
+
+		for _, parentHash := range commit.Parents {
+			visit(parentHash)
+		}
+		postorderCommits = append(postorderCommits, commit)
+	}
+	visit(headHash)
+
+	commits := []CommitModel{}
+	for i := len(postorderCommits) - 1; i >= 0; i-- {
+		commits = append(commits, postorderCommits[i])
+	}
+	return commits
+}
+
+// IsAncestor ...
+func (repo goGitRepository) IsAncestor(ancestor, descendant string) (bool, error) {
+	ancestorCommit, err := repo.repo.CommitObject(plumbing.NewHash(ancestor))
+	if err == plumbing.ErrObjectNotFound {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	descendantCommit, err := repo.repo.CommitObject(plumbing.NewHash(descendant))
+	if err != nil {
+		return false, err
+	}
+	return ancestorCommit.IsAncestor(descendantCommit)
+}
+
+// Add ...
+func (repo goGitRepository) Add(files []string) error {
+	worktree, err := repo.repo.Worktree()
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if _, err := worktree.Add(file); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Commit ...
+func (repo goGitRepository) Commit(message string, options CommitOptions) error {
+	worktree, err := repo.repo.Worktree()
+	if err != nil {
+		return err
+	}
+	signature, err := repo.signature()
+	if err != nil {
+		return err
+	}
+
+	commitOptions := &gogit.CommitOptions{Author: signature}
+	if options.Sign != nil {
+		if err := options.Sign.Validate(); err != nil {
+			return err
+		}
+		commitOptions.Signer = commandSigner{options: *options.Sign}
+	}
+
+	_, err = worktree.Commit(message, commitOptions)
+	return err
+}
+
+// Merge creates a merge commit (like 'git merge --no-ff'). If the branches diverged, the changes are merged
+// file by file: a file changed on both branches is a conflict, as go-git can not merge the contents.
+func (repo goGitRepository) Merge(branch, commitMessage string, options CommitOptions) error {
+	head, err := repo.repo.Head()
+	if err != nil {
+		return err
+	}
+	headCommit, err := repo.repo.CommitObject(head.Hash())
+	if err != nil {
+		return err
+	}
+
+	branchRef, err := repo.repo.Reference(plumbing.NewBranchReferenceName(branch), true)
+	if err != nil {
+		return fmt.Errorf("Failed to find branch (%s), error: %s", branch, err)
+	}
+	branchCommit, err := repo.repo.CommitObject(branchRef.Hash())
+	if err != nil {
+		return err
+	}
+
+	if headCommit.Hash == branchCommit.Hash {
+		return nil
+	}
+
+	// already merged
+	if isAncestor, err := branchCommit.IsAncestor(headCommit); err != nil {
+		return err
+	} else if isAncestor {
+		return nil
+	}
+
+	treeHash := branchCommit.TreeHash
+	if isAncestor, err := headCommit.IsAncestor(branchCommit); err != nil {
+		return err
+	} else if !isAncestor {
+		if treeHash, err = repo.mergeTree(headCommit, branchCommit); err != nil {
+			return fmt.Errorf("Failed to merge (%s) into (%s): %s", branch, head.Name().Short(), err)
+		}
+	}
+
+	signature, err := repo.signature()
+	if err != nil {
+		return err
+	}
+
+	mergeCommit := &object.Commit{
+		Author:       *signature,
+		Committer:    *signature,
+		Message:      commitMessage,
+		TreeHash:     treeHash,
+		ParentHashes: []plumbing.Hash{headCommit.Hash, branchCommit.Hash},
+	}
+	if options.Sign != nil {
+		if err := options.Sign.Validate(); err != nil {
+			return err
+		}
+		if mergeCommit.PGPSignature, err = signObject(*options.Sign, mergeCommit); err != nil {
+			return err
+		}
+	}
+
+	obj := repo.repo.Storer.NewEncodedObject()
+	if err := mergeCommit.Encode(obj); err != nil {
+		return err
+	}
+	mergeCommitHash, err := repo.repo.Storer.SetEncodedObject(obj)
+	if err != nil {
+		return err
+	}
+
+	if err := repo.repo.Storer.SetReference(plumbing.NewHashReference(head.Name(), mergeCommitHash)); err != nil {
+		return err
+	}
+
+	worktree, err := repo.repo.Worktree()
+	if err != nil {
+		return err
+	}
+	return worktree.Reset(&gogit.ResetOptions{
+		Commit: mergeCommitHash,
+		Mode:   gogit.HardReset,
+	})
+}
+
+// Tag ...
+func (repo goGitRepository) Tag(name string, options TagOptions) error {
+	if err := options.Validate(); err != nil {
+		return err
+	}
+
+	head, err := repo.repo.Head()
+	if err != nil {
+		return err
+	}
+
+	if options.Message == "" {
+		_, err = repo.repo.CreateTag(name, head.Hash(), nil)
+		return err
+	}
+
+	if _, err := repo.repo.Tag(name); err == nil {
+		return gogit.ErrTagExists
+	}
+
+	signature, err := repo.signature()
+	if err != nil {
+		return err
+	}
+
+	tag := &object.Tag{
+		Name:       name,
+		Tagger:     *signature,
+		Message:    strings.TrimSpace(options.Message) + "\n",
+		TargetType: plumbing.CommitObject,
+		Target:     head.Hash(),
+	}
+	if options.Sign != nil {
+		if tag.PGPSignature, err = signObject(*options.Sign, tag); err != nil {
+			return err
+		}
+	}
+
+	obj := repo.repo.Storer.NewEncodedObject()
+	if err := tag.Encode(obj); err != nil {
+		return err
+	}
+	tagHash, err := repo.repo.Storer.SetEncodedObject(obj)
+	if err != nil {
+		return err
+	}
+
+	return repo.repo.Storer.SetReference(plumbing.NewHashReference(plumbing.NewTagReferenceName(name), tagHash))
+}
@@ -0,0 +1,387 @@
+package git
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bitrise-tools/releaseman/versioning"
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-billy/v5/util"
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/stretchr/testify/require"
+)
+
+func newMemoryRepository(t *testing.T) (*gogit.Repository, billy.Filesystem) {
+	fs := memfs.New()
+	repo, err := gogit.Init(memory.NewStorage(), fs)
+	require.NoError(t, err)
+
+	cfg, err := repo.Config()
+	require.NoError(t, err)
+	cfg.User.Name = "Releaseman"
+	cfg.User.Email = "releaseman@bitrise.io"
+	require.NoError(t, repo.SetConfig(cfg))
+
+	return repo, fs
+}
+
+func commitFile(t *testing.T, repo *gogit.Repository, fs billy.Filesystem, file, message string, when time.Time, parents ...plumbing.Hash) plumbing.Hash {
+	require.NoError(t, util.WriteFile(fs, file, []byte(message), 0644))
+
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+	_, err = worktree.Add(file)
+	require.NoError(t, err)
+
+	signature := &object.Signature{Name: "Releaseman", Email: "releaseman@bitrise.io", When: when}
+	hash, err := worktree.Commit(message, &gogit.CommitOptions{Author: signature, Parents: parents})
+	require.NoError(t, err)
+	return hash
+}
+
+func TestGoGitRepository(t *testing.T) {
+	gitRepo, fs := newMemoryRepository(t)
+	repo := NewGoGitRepository(gitRepo)
+	worktree, err := gitRepo.Worktree()
+	require.NoError(t, err)
+
+	initialHash := commitFile(t, gitRepo, fs, "README.md", "feat: initial", time.Unix(1454498663, 0))
+	_, err = gitRepo.CreateTag("0.9.0", initialHash, nil)
+	require.NoError(t, err)
+	_, err = gitRepo.CreateTag("not-a-version", initialHash, nil)
+	require.NoError(t, err)
+
+	// long lived feature branch, with a commit dated before 0.9.0
+	require.NoError(t, worktree.Checkout(&gogit.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("feature"), Create: true}))
+	featureHash := commitFile(t, gitRepo, fs, "feature.txt", "feat: long lived feature\n\nBREAKING CHANGE: new config", time.Unix(1454498000, 0))
+
+	require.NoError(t, worktree.Checkout(&gogit.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("master")}))
+	fixHash := commitFile(t, gitRepo, fs, "fix.txt", "fix: bug one", time.Unix(1454498673, 0))
+	_, err = gitRepo.CreateTag("1.0.0", fixHash, nil)
+	require.NoError(t, err)
+
+	// merge the feature branch after 1.0.0
+	require.NoError(t, util.WriteFile(fs, "feature.txt", []byte("feat: long lived feature\n\nBREAKING CHANGE: new config"), 0644))
+	mergeHash := commitFile(t, gitRepo, fs, "feature.txt", "Merge feature", time.Unix(1454498683, 0), fixHash, featureHash)
+
+	t.Log("Branches")
+	{
+		branch, err := repo.CurrentBranchName()
+		require.NoError(t, err)
+		require.Equal(t, "master", branch)
+
+		branches, err := repo.LocalBranches()
+		require.NoError(t, err)
+		require.Equal(t, []string{"feature", "* master"}, branches)
+	}
+
+	t.Log("Tags")
+	{
+		tags, err := repo.Tags()
+		require.NoError(t, err)
+		require.Equal(t, []string{"0.9.0", "1.0.0", "not-a-version"}, tags)
+
+		originalRepository := CurrentRepository()
+		SetRepository(repo)
+		defer SetRepository(originalRepository)
+
+		taggedCommits, err := VersionTaggedCommits(DefaultTagFormat, versioning.SemVer{})
+		require.NoError(t, err)
+		require.Equal(t, 2, len(taggedCommits))
+		require.Equal(t, "0.9.0", taggedCommits[0].Tag)
+		require.Equal(t, "0.9.0", taggedCommits[0].Version)
+		require.Equal(t, initialHash.String(), taggedCommits[0].Hash)
+		require.Equal(t, "1.0.0", taggedCommits[1].Tag)
+		require.Equal(t, fixHash.String(), taggedCommits[1].Hash)
+		require.Equal(t, "fix", taggedCommits[1].Type)
+	}
+
+	t.Log("Log")
+	{
+		commits, err := repo.GetCommitsFrom(nil)
+		require.NoError(t, err)
+		require.Equal(t, 4, len(commits))
+		require.Equal(t, mergeHash.String(), commits[0].Hash)
+		require.Equal(t, []string{fixHash.String(), featureHash.String()}, commits[0].Parents)
+		require.Equal(t, []string{}, commits[0].Files)
+		require.Equal(t, initialHash.String(), commits[3].Hash)
+		require.Equal(t, []string{"README.md"}, commits[3].Files)
+
+		commits, err = repo.GetCommitsFrom(&CommitModel{Hash: fixHash.String()})
+		require.NoError(t, err)
+		require.Equal(t, 2, len(commits))
+		require.Equal(t, mergeHash.String(), commits[0].Hash)
+		require.Equal(t, featureHash.String(), commits[1].Hash)
+		require.Equal(t, []string{"feature.txt"}, commits[1].Files)
+		require.Equal(t, "long lived feature", commits[1].Description)
+		require.Equal(t, "BREAKING CHANGE: new config", commits[1].Body)
+		require.Equal(t, true, commits[1].Breaking)
+
+		// the range does not depend on HEAD
+		commits, err = repo.GetCommitsBetween(&CommitModel{Hash: initialHash.String()}, CommitModel{Hash: fixHash.String()})
+		require.NoError(t, err)
+		require.Equal(t, 1, len(commits))
+		require.Equal(t, fixHash.String(), commits[0].Hash)
+
+		commits, err = repo.GetCommitsBetween(nil, CommitModel{Hash: featureHash.String()})
+		require.NoError(t, err)
+		require.Equal(t, 2, len(commits))
+		require.Equal(t, featureHash.String(), commits[0].Hash)
+		require.Equal(t, initialHash.String(), commits[1].Hash)
+
+		firstCommit, err := repo.FirstCommit()
+		require.NoError(t, err)
+		require.Equal(t, initialHash.String(), firstCommit.Hash)
+
+		latestCommit, err := repo.LatestCommit()
+		require.NoError(t, err)
+		require.Equal(t, mergeHash.String(), latestCommit.Hash)
+	}
+
+	t.Log("Commit, merge and tag")
+	{
+		require.NoError(t, worktree.Checkout(&gogit.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("release"), Create: true}))
+		require.NoError(t, repo.CheckoutBranch("master"))
+
+		require.NoError(t, util.WriteFile(fs, "CHANGELOG.md", []byte("## Changelog"), 0644))
+
+		changed, err := repo.AreUncommitedChanges()
+		require.NoError(t, err)
+		require.Equal(t, true, changed)
+
+		changes, err := repo.GetChangedFiles()
+		require.NoError(t, err)
+		require.Equal(t, []string{"CHANGELOG.md"}, changes)
+
+		require.NoError(t, repo.Add(changes))
+		require.NoError(t, repo.Commit("v1.1.0", CommitOptions{}))
+
+		changed, err = repo.AreUncommitedChanges()
+		require.NoError(t, err)
+		require.Equal(t, false, changed)
+
+		require.NoError(t, repo.CheckoutBranch("release"))
+		require.NoError(t, repo.Merge("master", "Merge master into release, release: v1.1.0", CommitOptions{}))
+		require.NoError(t, repo.Tag("1.1.0", TagOptions{}))
+
+		latestCommit, err := repo.LatestCommit()
+		require.NoError(t, err)
+		require.Equal(t, "Merge master into release, release: v1.1.0", latestCommit.Message)
+		require.Equal(t, 2, len(latestCommit.Parents))
+		require.Equal(t, mergeHash.String(), latestCommit.Parents[0])
+
+		exist, err := util.ReadFile(fs, "CHANGELOG.md")
+		require.NoError(t, err)
+		require.Equal(t, "## Changelog", string(exist))
+
+		taggedCommit, err := repo.CommitOfTag("1.1.0")
+		require.NoError(t, err)
+		require.Equal(t, latestCommit.Hash, taggedCommit.Hash)
+		require.Equal(t, "1.1.0", taggedCommit.Tag)
+
+		for _, revision := range []string{"1.1.0", "release", latestCommit.Hash} {
+			commit, err := repo.CommitOf(revision)
+			require.NoError(t, err)
+			require.Equal(t, latestCommit.Hash, commit.Hash)
+		}
+		_, err = repo.CommitOf("missing")
+		require.Error(t, err)
+
+		t.Log("conflicting changes can not be merged")
+		require.NoError(t, repo.CheckoutBranch("feature"))
+		commitFile(t, gitRepo, fs, "feature.txt", "feat: diverged", time.Unix(1454498693, 0))
+		require.Error(t, repo.Merge("master", "Merge master into feature", CommitOptions{}))
+	}
+}
+
+func TestGoGitMerge(t *testing.T) {
+	gitRepo, fs := newMemoryRepository(t)
+	repo := NewGoGitRepository(gitRepo)
+	worktree, err := gitRepo.Worktree()
+	require.NoError(t, err)
+
+	commitFile(t, gitRepo, fs, "README.md", "feat: initial", time.Unix(1454498663, 0))
+	require.NoError(t, worktree.Checkout(&gogit.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("develop"), Create: true}))
+
+	release := func(version string) {
+		require.NoError(t, repo.CheckoutBranch("master"))
+		require.NoError(t, repo.Merge("develop", "Merge develop into master, release: v"+version, CommitOptions{}))
+		require.NoError(t, repo.Tag(version, TagOptions{}))
+		require.NoError(t, repo.CheckoutBranch("develop"))
+	}
+
+	t.Log("the release branch diverges from the second release on")
+	{
+		commitFile(t, gitRepo, fs, "feature.txt", "feat: first", time.Unix(1454498673, 0))
+		release("1.0.0")
+		commitFile(t, gitRepo, fs, "docs/guide.md", "docs: guide", time.Unix(1454498683, 0))
+		commitFile(t, gitRepo, fs, "feature.txt", "feat: second", time.Unix(1454498693, 0))
+		developHead, err := repo.BranchHead("develop")
+		require.NoError(t, err)
+
+		release("1.1.0")
+
+		releaseCommit, err := repo.CommitOfTag("1.1.0")
+		require.NoError(t, err)
+		require.Equal(t, "Merge develop into master, release: v1.1.0", releaseCommit.Message)
+		require.Equal(t, developHead, releaseCommit.Parents[1])
+
+		// the release has the content of the development branch
+		require.NoError(t, repo.CheckoutBranch("master"))
+		for file, content := range map[string]string{"README.md": "feat: initial", "feature.txt": "feat: second", "docs/guide.md": "docs: guide"} {
+			fileContent, err := util.ReadFile(fs, file)
+			require.NoError(t, err)
+			require.Equal(t, content, string(fileContent))
+		}
+		changed, err := repo.AreUncommitedChanges()
+		require.NoError(t, err)
+		require.Equal(t, false, changed)
+	}
+
+	t.Log("changes of the release branch are kept")
+	{
+		hotfixHash := commitFile(t, gitRepo, fs, "hotfix.txt", "fix: hotfix", time.Unix(1454498703, 0))
+		require.NoError(t, repo.CheckoutBranch("develop"))
+		commitFile(t, gitRepo, fs, "feature.txt", "feat: third", time.Unix(1454498713, 0))
+		release("1.2.0")
+
+		releaseCommit, err := repo.CommitOfTag("1.2.0")
+		require.NoError(t, err)
+		require.Equal(t, hotfixHash.String(), releaseCommit.Parents[0])
+
+		require.NoError(t, repo.CheckoutBranch("master"))
+		for file, content := range map[string]string{"hotfix.txt": "fix: hotfix", "feature.txt": "feat: third"} {
+			fileContent, err := util.ReadFile(fs, file)
+			require.NoError(t, err)
+			require.Equal(t, content, string(fileContent))
+		}
+	}
+
+	t.Log("merging a merged branch is a no-op")
+	{
+		head, err := repo.BranchHead("master")
+		require.NoError(t, err)
+		require.NoError(t, repo.Merge("develop", "Merge develop into master", CommitOptions{}))
+		newHead, err := repo.BranchHead("master")
+		require.NoError(t, err)
+		require.Equal(t, head, newHead)
+	}
+}
+
+func TestBranchVersionTaggedCommits(t *testing.T) {
+	gitRepo, fs := newMemoryRepository(t)
+	repo := NewGoGitRepository(gitRepo)
+	worktree, err := gitRepo.Worktree()
+	require.NoError(t, err)
+
+	originalRepository := repository
+	SetRepository(repo)
+	defer SetRepository(originalRepository)
+
+	tag := func(name string, hash plumbing.Hash) {
+		_, err := gitRepo.CreateTag(name, hash, nil)
+		require.NoError(t, err)
+	}
+
+	tag("1.4.0", commitFile(t, gitRepo, fs, "README.md", "feat: initial", time.Unix(1454498663, 0)))
+	require.NoError(t, worktree.Checkout(&gogit.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("release-1.4"), Create: true}))
+	require.NoError(t, worktree.Checkout(&gogit.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("master")}))
+	tag("2.0.0", commitFile(t, gitRepo, fs, "README.md", "feat!: new api", time.Unix(1454498673, 0)))
+
+	// hotfix of the maintenance line, tagged after 2.0.0
+	require.NoError(t, worktree.Checkout(&gogit.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("release-1.4")}))
+	tag("1.4.3", commitFile(t, gitRepo, fs, "fix.txt", "fix: hotfix", time.Unix(1454498683, 0)))
+
+	t.Log("all tags are sorted by version")
+	{
+		taggedCommits, err := VersionTaggedCommits(DefaultTagFormat, versioning.SemVer{})
+		require.NoError(t, err)
+		require.Equal(t, 3, len(taggedCommits))
+		require.Equal(t, "1.4.0", taggedCommits[0].Version)
+		require.Equal(t, "1.4.3", taggedCommits[1].Version)
+		require.Equal(t, "2.0.0", taggedCommits[2].Version)
+	}
+
+	t.Log("tags reachable from the branch")
+	{
+		taggedCommits, err := BranchVersionTaggedCommits(DefaultTagFormat, versioning.SemVer{}, "master")
+		require.NoError(t, err)
+		require.Equal(t, 2, len(taggedCommits))
+		require.Equal(t, "1.4.0", taggedCommits[0].Version)
+		require.Equal(t, "2.0.0", taggedCommits[1].Version)
+
+		taggedCommits, err = BranchVersionTaggedCommits(DefaultTagFormat, versioning.SemVer{}, "release-1.4")
+		require.NoError(t, err)
+		require.Equal(t, 2, len(taggedCommits))
+		require.Equal(t, "1.4.0", taggedCommits[0].Version)
+		require.Equal(t, "1.4.3", taggedCommits[1].Version)
+	}
+}
+
+func TestReleaseBranchVersionTaggedCommits(t *testing.T) {
+	gitRepo, fs := newMemoryRepository(t)
+	repo := NewGoGitRepository(gitRepo)
+	worktree, err := gitRepo.Worktree()
+	require.NoError(t, err)
+
+	originalRepository := repository
+	SetRepository(repo)
+	defer SetRepository(originalRepository)
+
+	// develop/master flow: the release tags are on the merge commits of master
+	commitFile(t, gitRepo, fs, "README.md", "feat: initial", time.Unix(1454498663, 0))
+	require.NoError(t, worktree.Checkout(&gogit.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("develop"), Create: true}))
+
+	release := func(version string) {
+		require.NoError(t, repo.CheckoutBranch("master"))
+		require.NoError(t, repo.Merge("develop", "Merge develop into master, release: v"+version, CommitOptions{}))
+		require.NoError(t, repo.Tag(version, TagOptions{}))
+		require.NoError(t, repo.CheckoutBranch("develop"))
+	}
+
+	commitFile(t, gitRepo, fs, "feature.txt", "feat: first", time.Unix(1454498673, 0))
+	commitFile(t, gitRepo, fs, "CHANGELOG.md", "v1.0.0", time.Unix(1454498683, 0))
+	release("1.0.0")
+	commitFile(t, gitRepo, fs, "feature.txt", "feat: second", time.Unix(1454498693, 0))
+	commitFile(t, gitRepo, fs, "CHANGELOG.md", "v1.1.0", time.Unix(1454498703, 0))
+	release("1.1.0")
+	commitFile(t, gitRepo, fs, "feature.txt", "feat: third", time.Unix(1454498713, 0))
+
+	t.Log("the merge commits are not reachable from the development branch")
+	{
+		taggedCommits, err := BranchVersionTaggedCommits(DefaultTagFormat, versioning.SemVer{}, "develop")
+		require.NoError(t, err)
+		for _, taggedCommit := range taggedCommits {
+			require.NotEqual(t, "1.1.0", taggedCommit.Version)
+		}
+	}
+
+	t.Log("tags reachable from the development or the release branch")
+	{
+		taggedCommits, err := BranchVersionTaggedCommits(DefaultTagFormat, versioning.SemVer{}, "develop", "master", "")
+		require.NoError(t, err)
+		require.Equal(t, 2, len(taggedCommits))
+		require.Equal(t, "1.0.0", taggedCommits[0].Version)
+		require.Equal(t, "1.1.0", taggedCommits[1].Version)
+
+		// only the commits since the last release are collected on the development branch
+		commits, err := GetCommitsFrom(&(taggedCommits[1]))
+		require.NoError(t, err)
+		require.Equal(t, 1, len(commits))
+		require.Equal(t, "feat: third", commits[0].Message)
+	}
+}
+
+func TestSplitCommitMessage(t *testing.T) {
+	subject, body := splitCommitMessage("feat: sections\n")
+	require.Equal(t, "feat: sections", subject)
+	require.Equal(t, "", body)
+
+	subject, body = splitCommitMessage("feat: sections\nin two lines\n\nbody  \n\nRefs #1\n")
+	require.Equal(t, "feat: sections in two lines", subject)
+	require.Equal(t, "body\n\nRefs #1", body)
+}
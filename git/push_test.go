@@ -0,0 +1,147 @@
+package git
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=Releaseman", "GIT_AUTHOR_EMAIL=releaseman@bitrise.io", "GIT_COMMITTER_NAME=Releaseman", "GIT_COMMITTER_EMAIL=releaseman@bitrise.io")
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+	return strings.TrimSpace(string(out))
+}
+
+// newRepositoryWithRemote creates a repository with master and release branches,
+// pushed to a local bare repository (origin).
+func newRepositoryWithRemote(t *testing.T) (string, string) {
+	tmpDir, err := ioutil.TempDir("", "releaseman")
+	require.NoError(t, err)
+
+	remoteDir := filepath.Join(tmpDir, "remote.git")
+	localDir := filepath.Join(tmpDir, "local")
+	require.NoError(t, os.MkdirAll(localDir, 0755))
+
+	runGit(t, tmpDir, "init", "--quiet", "--bare", remoteDir)
+	runGit(t, localDir, "init", "--quiet")
+	runGit(t, localDir, "checkout", "--quiet", "-b", "master")
+	runGit(t, localDir, "commit", "--quiet", "--allow-empty", "-m", "feat: initial")
+	runGit(t, localDir, "branch", "release")
+	runGit(t, localDir, "tag", "1.0.0")
+	runGit(t, localDir, "remote", "add", "origin", remoteDir)
+	runGit(t, localDir, "push", "--quiet", "origin", "master", "release", "1.0.0")
+
+	return localDir, remoteDir
+}
+
+func testPush(t *testing.T, repo Repository, localDir, remoteDir string) {
+	t.Log("remote branch heads")
+	{
+		head, err := repo.RemoteBranchHead("origin", "master")
+		require.NoError(t, err)
+		require.Equal(t, runGit(t, localDir, "rev-parse", "master"), head)
+
+		head, err = repo.RemoteBranchHead("origin", "missing")
+		require.NoError(t, err)
+		require.Equal(t, "", head)
+	}
+
+	t.Log("push branches and tag")
+	{
+		remoteHead := runGit(t, localDir, "rev-parse", "master")
+		runGit(t, localDir, "commit", "--quiet", "--allow-empty", "-m", "v1.1.0")
+		runGit(t, localDir, "branch", "--force", "release", "master")
+		runGit(t, localDir, "tag", "1.1.0", "release")
+		localHead := runGit(t, localDir, "rev-parse", "master")
+
+		isAncestor, err := repo.IsAncestor(remoteHead, localHead)
+		require.NoError(t, err)
+		require.Equal(t, true, isAncestor)
+
+		require.NoError(t, repo.Push("origin", []string{"master", "release"}, []string{"1.1.0"}))
+		require.Equal(t, localHead, runGit(t, remoteDir, "rev-parse", "master"))
+		require.Equal(t, localHead, runGit(t, remoteDir, "rev-parse", "release"))
+		require.Equal(t, localHead, runGit(t, remoteDir, "rev-list", "-n", "1", "1.1.0"))
+
+		tags, err := repo.RemoteTags("origin")
+		require.NoError(t, err)
+		require.Equal(t, []string{"1.0.0", "1.1.0"}, tags)
+	}
+
+	t.Log("a rejected ref fails the whole push")
+	{
+		// somebody else updated the release branch on the remote
+		runGit(t, localDir, "checkout", "--quiet", "-b", "hotfix")
+		runGit(t, localDir, "commit", "--quiet", "--allow-empty", "-m", "fix: hotfix")
+		runGit(t, localDir, "push", "--quiet", "origin", "hotfix:release")
+		remoteReleaseHead := runGit(t, localDir, "rev-parse", "hotfix")
+		runGit(t, localDir, "checkout", "--quiet", "master")
+
+		runGit(t, localDir, "commit", "--quiet", "--allow-empty", "-m", "v1.2.0")
+		runGit(t, localDir, "branch", "--force", "release", "master")
+		runGit(t, localDir, "tag", "1.2.0", "release")
+
+		isAncestor, err := repo.IsAncestor(remoteReleaseHead, runGit(t, localDir, "rev-parse", "release"))
+		require.NoError(t, err)
+		require.Equal(t, false, isAncestor)
+
+		isAncestor, err = repo.IsAncestor("0123456789012345678901234567890123456789", runGit(t, localDir, "rev-parse", "release"))
+		require.NoError(t, err)
+		require.Equal(t, false, isAncestor)
+
+		masterHead := runGit(t, remoteDir, "rev-parse", "master")
+		require.Error(t, repo.Push("origin", []string{"master", "release"}, []string{"1.2.0"}))
+		require.Equal(t, masterHead, runGit(t, remoteDir, "rev-parse", "master"))
+		require.Equal(t, remoteReleaseHead, runGit(t, remoteDir, "rev-parse", "release"))
+
+		tags, err := repo.RemoteTags("origin")
+		require.NoError(t, err)
+		require.Equal(t, []string{"1.0.0", "1.1.0"}, tags)
+	}
+}
+
+func TestExecRepositoryPush(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+
+	localDir, remoteDir := newRepositoryWithRemote(t)
+	defer func() {
+		require.NoError(t, os.RemoveAll(filepath.Dir(localDir)))
+	}()
+
+	workDir, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(localDir))
+	defer func() {
+		require.NoError(t, os.Chdir(workDir))
+	}()
+
+	testPush(t, NewExecRepository(), localDir, remoteDir)
+}
+
+func TestGoGitRepositoryPush(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		// the file transport of go-git runs git-receive-pack
+		t.Skip("git not found")
+	}
+
+	localDir, remoteDir := newRepositoryWithRemote(t)
+	defer func() {
+		require.NoError(t, os.RemoveAll(filepath.Dir(localDir)))
+	}()
+
+	gitRepo, err := gogit.PlainOpen(localDir)
+	require.NoError(t, err)
+
+	testPush(t, NewGoGitRepository(gitRepo), localDir, remoteDir)
+}
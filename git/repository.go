@@ -0,0 +1,73 @@
+package git
+
+//=======================================
+// Model
+//=======================================
+
+// Repository is the git backend of releaseman.
+type Repository interface {
+	// GitDir returns the absolute path of the .git directory
+	GitDir() (string, error)
+
+	// Branches
+	LocalBranches() ([]string, error)
+	CurrentBranchName() (string, error)
+	CheckoutBranch(branch string) error
+	BranchHead(branch string) (string, error)
+	// ResetBranch points the branch to the given commit, the working tree is reset as well
+	// if the branch is checked out (discarding uncommitted changes and an unfinished merge).
+	ResetBranch(branch, hash string) error
+
+	// Working tree
+	AreUncommitedChanges() (bool, error)
+	GetChangedFiles() ([]string, error)
+
+	// Tags
+	Tags() ([]string, error)
+	DeleteTag(tag string) error
+	CommitOfTag(tag string) (CommitModel, error)
+
+	// Remotes
+	Remotes() ([]string, error)
+	// RemoteBranchesContaining returns the remote-tracking branches which contain the given commit.
+	RemoteBranchesContaining(hash string) ([]string, error)
+	// RemoteTags lists the tags of the remote, it connects to the remote.
+	RemoteTags(remote string) ([]string, error)
+	// RemoteBranchHead returns the head of the branch on the remote (empty if the branch does not exist there),
+	// it connects to the remote.
+	RemoteBranchHead(remote, branch string) (string, error)
+	// Push pushes the branches and the tags to the remote atomically: either every ref is updated or none.
+	Push(remote string, branches, tags []string) error
+
+	// Log
+	FirstCommit() (CommitModel, error)
+	LatestCommit() (CommitModel, error)
+	// CommitOf returns the commit of the revision, e.g. a hash, a tag or a branch
+	CommitOf(revision string) (CommitModel, error)
+	GetCommitsFrom(startCommitPtr *CommitModel) ([]CommitModel, error)
+	// GetCommitsBetween returns the commits reachable from the end commit but not from startCommitPtr,
+	// independently of HEAD. A nil startCommitPtr means the whole history of the end commit.
+	GetCommitsBetween(startCommitPtr *CommitModel, endCommit CommitModel) ([]CommitModel, error)
+	// IsAncestor returns true if the ancestor commit is reachable from the descendant commit
+	// (or they are the same), an unknown ancestor commit is not an ancestor.
+	IsAncestor(ancestor, descendant string) (bool, error)
+
+	// Commit, merge and tag
+	Add(files []string) error
+	Commit(message string, options CommitOptions) error
+	Merge(branch, commitMessage string, options CommitOptions) error
+	Tag(name string, options TagOptions) error
+}
+
+// repository is used by the package level git functions
+var repository = NewExecRepository()
+
+// SetRepository sets the backend of the package level git functions.
+func SetRepository(repo Repository) {
+	repository = repo
+}
+
+// CurrentRepository ...
+func CurrentRepository() Repository {
+	return repository
+}
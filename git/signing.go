@@ -0,0 +1,150 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+//=======================================
+// Consts
+//=======================================
+
+const (
+	// GPGSigningFormat ...
+	GPGSigningFormat = "gpg"
+	// SSHSigningFormat ...
+	SSHSigningFormat = "ssh"
+)
+
+//=======================================
+// Models
+//=======================================
+
+// SigningOptions describes how to sign commits and tags.
+type SigningOptions struct {
+	// Format is gpg (default) or ssh
+	Format string
+	// Key is the gpg key id or the path of the ssh key, empty means the default gpg key
+	Key string
+}
+
+// CommitOptions ...
+type CommitOptions struct {
+	// Sign signs the commit, nil means unsigned commit
+	Sign *SigningOptions
+}
+
+// TagOptions ...
+type TagOptions struct {
+	// Message creates an annotated tag, empty message means lightweight tag
+	Message string
+	// Sign signs the tag, it requires a Message
+	Sign *SigningOptions
+}
+
+//=======================================
+// Utility
+//=======================================
+
+// Validate ...
+func (options SigningOptions) Validate() error {
+	switch options.Format {
+	case "", GPGSigningFormat:
+	case SSHSigningFormat:
+		if options.Key == "" {
+			return fmt.Errorf("Missing ssh signing key")
+		}
+	default:
+		return fmt.Errorf("Invalid signing format (%s), options: %s, %s", options.Format, GPGSigningFormat, SSHSigningFormat)
+	}
+	return nil
+}
+
+// Validate ...
+func (options TagOptions) Validate() error {
+	if options.Sign != nil {
+		if options.Message == "" {
+			return fmt.Errorf("Signed tags require a message")
+		}
+		return options.Sign.Validate()
+	}
+	return nil
+}
+
+// gitConfigArgs returns the git options which select the signing format and key.
+func (options SigningOptions) gitConfigArgs() []string {
+	format := "openpgp"
+	if options.Format == SSHSigningFormat {
+		format = SSHSigningFormat
+	}
+	args := []string{"-c", "gpg.format=" + format}
+	if options.Key != "" {
+		args = append(args, "-c", "user.signingkey="+options.Key)
+	}
+	return args
+}
+
+// commandSigner signs git objects by running gpg or ssh-keygen, the same way git does.
+type commandSigner struct {
+	options SigningOptions
+}
+
+// Sign ...
+func (signer commandSigner) Sign(message io.Reader) ([]byte, error) {
+	var args []string
+	if signer.options.Format == SSHSigningFormat {
+		args = []string{"ssh-keygen", "-Y", "sign", "-n", "git", "-f", signer.options.Key}
+	} else {
+		args = []string{"gpg", "--status-fd=2", "-bsa"}
+		if signer.options.Key != "" {
+			args = append(args, "-u", signer.options.Key)
+		}
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = message
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		exitCode := -1
+		if exitError, ok := err.(*exec.ExitError); ok {
+			exitCode = exitError.ExitCode()
+		}
+		return nil, &CommandError{
+			Command:  strings.Join(args, " "),
+			ExitCode: exitCode,
+			Stdout:   strings.TrimSpace(stdout.String()),
+			Stderr:   strings.TrimSpace(stderr.String()),
+			Err:      err,
+		}
+	}
+	return stdout.Bytes(), nil
+}
+
+// signableObject is a commit or a tag.
+type signableObject interface {
+	EncodeWithoutSignature(o plumbing.EncodedObject) error
+}
+
+func signObject(options SigningOptions, obj signableObject) (string, error) {
+	encoded := &plumbing.MemoryObject{}
+	if err := obj.EncodeWithoutSignature(encoded); err != nil {
+		return "", err
+	}
+	reader, err := encoded.Reader()
+	if err != nil {
+		return "", err
+	}
+
+	signature, err := commandSigner{options: options}.Sign(reader)
+	if err != nil {
+		return "", err
+	}
+	return string(signature), nil
+}
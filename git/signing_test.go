@@ -0,0 +1,131 @@
+package git
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigningOptionsValidate(t *testing.T) {
+	require.NoError(t, SigningOptions{}.Validate())
+	require.NoError(t, SigningOptions{Format: GPGSigningFormat, Key: "ABCDEF"}.Validate())
+	require.NoError(t, SigningOptions{Format: SSHSigningFormat, Key: "~/.ssh/id_ed25519"}.Validate())
+	require.Error(t, SigningOptions{Format: SSHSigningFormat}.Validate())
+	require.Error(t, SigningOptions{Format: "x509"}.Validate())
+
+	require.NoError(t, TagOptions{}.Validate())
+	require.NoError(t, TagOptions{Message: "Release 1.1.0"}.Validate())
+	require.Error(t, TagOptions{Sign: &SigningOptions{}}.Validate())
+}
+
+// newSigningRepository creates a repository, an ssh signing key and an allowed signers file to verify the signatures.
+func newSigningRepository(t *testing.T) (string, SigningOptions) {
+	tmpDir, err := ioutil.TempDir("", "releaseman")
+	require.NoError(t, err)
+
+	keyPth := filepath.Join(tmpDir, "id_ed25519")
+	out, err := exec.Command("ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-C", "releaseman", "-f", keyPth).CombinedOutput()
+	require.NoError(t, err, string(out))
+	publicKey, err := ioutil.ReadFile(keyPth + ".pub")
+	require.NoError(t, err)
+	allowedSigners := "releaseman@bitrise.io namespaces=\"git\" " + string(publicKey)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(tmpDir, "allowed_signers"), []byte(allowedSigners), 0644))
+
+	repoDir := filepath.Join(tmpDir, "repo")
+	require.NoError(t, os.MkdirAll(repoDir, 0755))
+	runGit(t, repoDir, "init", "--quiet")
+	runGit(t, repoDir, "config", "user.name", "Releaseman")
+	runGit(t, repoDir, "config", "user.email", "releaseman@bitrise.io")
+	runGit(t, repoDir, "config", "gpg.ssh.allowedSignersFile", filepath.Join(tmpDir, "allowed_signers"))
+	runGit(t, repoDir, "checkout", "--quiet", "-b", "master")
+	runGit(t, repoDir, "commit", "--quiet", "--allow-empty", "-m", "feat: initial")
+	runGit(t, repoDir, "branch", "release")
+
+	return repoDir, SigningOptions{Format: SSHSigningFormat, Key: keyPth}
+}
+
+func testSigning(t *testing.T, repo Repository, repoDir string, signing SigningOptions) {
+	t.Log("signed commit")
+	{
+		require.NoError(t, ioutil.WriteFile(filepath.Join(repoDir, "CHANGELOG.md"), []byte("## Changelog"), 0644))
+		require.NoError(t, repo.Add([]string{"CHANGELOG.md"}))
+		require.NoError(t, repo.Commit("v1.1.0", CommitOptions{Sign: &signing}))
+		require.Contains(t, runGit(t, repoDir, "cat-file", "-p", "HEAD"), "gpgsig -----BEGIN SSH SIGNATURE-----")
+		runGit(t, repoDir, "verify-commit", "HEAD")
+	}
+
+	t.Log("signed merge commit")
+	{
+		require.NoError(t, repo.CheckoutBranch("release"))
+		require.NoError(t, repo.Merge("master", "Merge master into release, release: v1.1.0", CommitOptions{Sign: &signing}))
+		require.Equal(t, 3, len(strings.Fields(runGit(t, repoDir, "rev-list", "--parents", "-n", "1", "HEAD"))))
+		runGit(t, repoDir, "verify-commit", "HEAD")
+	}
+
+	t.Log("annotated tag")
+	{
+		require.NoError(t, repo.Tag("1.1.0", TagOptions{Message: "Release 1.1.0\n\nFeatures:\n* changelog\n"}))
+		require.Equal(t, "tag", runGit(t, repoDir, "cat-file", "-t", "1.1.0"))
+		require.Equal(t, "Release 1.1.0\n\nFeatures:\n* changelog", runGit(t, repoDir, "tag", "--list", "--format=%(contents)", "1.1.0"))
+
+		taggedCommit, err := repo.CommitOfTag("1.1.0")
+		require.NoError(t, err)
+		require.Equal(t, runGit(t, repoDir, "rev-parse", "HEAD"), taggedCommit.Hash)
+	}
+
+	t.Log("signed tag")
+	{
+		require.NoError(t, repo.Tag("1.1.1", TagOptions{Message: "Release 1.1.1", Sign: &signing}))
+		require.Contains(t, runGit(t, repoDir, "cat-file", "-p", "1.1.1"), "-----BEGIN SSH SIGNATURE-----")
+		runGit(t, repoDir, "verify-tag", "1.1.1")
+
+		require.Error(t, repo.Tag("1.1.1", TagOptions{Message: "Release 1.1.1"}))
+	}
+}
+
+func TestExecRepositorySigning(t *testing.T) {
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("ssh-keygen not found")
+	}
+
+	repoDir, signing := newSigningRepository(t)
+	defer func() {
+		require.NoError(t, os.RemoveAll(filepath.Dir(repoDir)))
+	}()
+
+	workDir, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(repoDir))
+	defer func() {
+		require.NoError(t, os.Chdir(workDir))
+	}()
+
+	testSigning(t, NewExecRepository(), repoDir, signing)
+}
+
+func TestGoGitRepositorySigning(t *testing.T) {
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("ssh-keygen not found")
+	}
+
+	repoDir, signing := newSigningRepository(t)
+	defer func() {
+		require.NoError(t, os.RemoveAll(filepath.Dir(repoDir)))
+	}()
+
+	gitRepo, err := gogit.PlainOpen(repoDir)
+	require.NoError(t, err)
+
+	testSigning(t, NewGoGitRepository(gitRepo), repoDir, signing)
+}
+
+func TestCommandSignerError(t *testing.T) {
+	_, err := commandSigner{options: SigningOptions{Format: SSHSigningFormat, Key: "/not/existing/key"}}.Sign(strings.NewReader("message"))
+	require.Error(t, err)
+}
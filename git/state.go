@@ -0,0 +1,135 @@
+package git
+
+import (
+	"fmt"
+)
+
+//=======================================
+// Models
+//=======================================
+
+// RepositoryState is a snapshot of the refs a release modifies.
+type RepositoryState struct {
+	CurrentBranch string            `json:"current_branch"`
+	BranchHeads   map[string]string `json:"branch_heads"`
+	Tags          []string          `json:"tags"`
+}
+
+//=======================================
+// Utility
+//=======================================
+
+func shortHash(hash string) string {
+	if len(hash) < 7 {
+		return hash
+	}
+	return hash[0:7]
+}
+
+//=======================================
+// Main
+//=======================================
+
+// CaptureState saves the current branch, the heads of the given branches and the existing tags.
+func CaptureState(branches ...string) (RepositoryState, error) {
+	currentBranch, err := CurrentBranchName()
+	if err != nil {
+		return RepositoryState{}, err
+	}
+
+	branchHeads := map[string]string{}
+	for _, branch := range branches {
+		head, err := BranchHead(branch)
+		if err != nil {
+			return RepositoryState{}, err
+		}
+		branchHeads[branch] = head
+	}
+
+	tags, err := Tags()
+	if err != nil {
+		return RepositoryState{}, err
+	}
+
+	return RepositoryState{
+		CurrentBranch: currentBranch,
+		BranchHeads:   branchHeads,
+		Tags:          tags,
+	}, nil
+}
+
+// RestoreState resets the saved branches to their saved heads, deletes the tags created since the state was saved
+// and checks out the saved current branch. It returns the list of the undone changes.
+func RestoreState(state RepositoryState) ([]string, error) {
+	undone := []string{}
+
+	// reset the checked out branch first, to clean the working tree (and an unfinished merge)
+	currentBranch, err := CurrentBranchName()
+	if err != nil {
+		return undone, err
+	}
+	if head, ok := state.BranchHeads[currentBranch]; ok {
+		if err := resetBranchIfMoved(currentBranch, head, &undone); err != nil {
+			return undone, err
+		}
+
+		if areChanges, err := AreUncommitedChanges(); err != nil {
+			return undone, err
+		} else if areChanges {
+			if err := ResetBranch(currentBranch, head); err != nil {
+				return undone, err
+			}
+			undone = append(undone, fmt.Sprintf("discarded uncommitted changes on branch %s", currentBranch))
+		}
+	}
+
+	if currentBranch != state.CurrentBranch {
+		if err := CheckoutBranch(state.CurrentBranch); err != nil {
+			return undone, err
+		}
+		undone = append(undone, fmt.Sprintf("checked out branch %s (instead of %s)", state.CurrentBranch, currentBranch))
+	}
+
+	for branch, head := range state.BranchHeads {
+		if err := resetBranchIfMoved(branch, head, &undone); err != nil {
+			return undone, err
+		}
+	}
+
+	existingTags := map[string]bool{}
+	for _, tag := range state.Tags {
+		existingTags[tag] = true
+	}
+
+	tags, err := Tags()
+	if err != nil {
+		return undone, err
+	}
+	for _, tag := range tags {
+		if existingTags[tag] {
+			continue
+		}
+		if err := DeleteTag(tag); err != nil {
+			return undone, err
+		}
+		undone = append(undone, fmt.Sprintf("deleted tag %s", tag))
+	}
+
+	return undone, nil
+}
+
+func resetBranchIfMoved(branch, head string, undone *[]string) error {
+	currentHead, err := BranchHead(branch)
+	if err != nil {
+		return err
+	}
+	if currentHead == head {
+		return nil
+	}
+
+	if err := ResetBranch(branch, head); err != nil {
+		return err
+	}
+	*undone = append(*undone, fmt.Sprintf("reset branch %s from %s to %s", branch, shortHash(currentHead), shortHash(head)))
+	return nil
+}
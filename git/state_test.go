@@ -0,0 +1,82 @@
+package git
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/util"
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaptureAndRestoreState(t *testing.T) {
+	gitRepo, fs := newMemoryRepository(t)
+	originalRepository := CurrentRepository()
+	SetRepository(NewGoGitRepository(gitRepo))
+	defer SetRepository(originalRepository)
+
+	worktree, err := gitRepo.Worktree()
+	require.NoError(t, err)
+
+	initialHash := commitFile(t, gitRepo, fs, "README.md", "feat: initial", time.Unix(1454498663, 0))
+	_, err = gitRepo.CreateTag("1.0.0", initialHash, nil)
+	require.NoError(t, err)
+	require.NoError(t, worktree.Checkout(&gogit.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("release"), Create: true}))
+	require.NoError(t, worktree.Checkout(&gogit.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("master")}))
+	devHash := commitFile(t, gitRepo, fs, "fix.txt", "fix: bug one", time.Unix(1454498673, 0))
+
+	t.Log("capture")
+	state, err := CaptureState("master", "release")
+	require.NoError(t, err)
+	require.Equal(t, "master", state.CurrentBranch)
+	require.Equal(t, map[string]string{"master": devHash.String(), "release": initialHash.String()}, state.BranchHeads)
+	require.Equal(t, []string{"1.0.0"}, state.Tags)
+
+	t.Log("nothing to restore")
+	{
+		undone, err := RestoreState(state)
+		require.NoError(t, err)
+		require.Equal(t, []string{}, undone)
+	}
+
+	t.Log("restore a half done release")
+	{
+		require.NoError(t, util.WriteFile(fs, "CHANGELOG.md", []byte("## Changelog"), 0644))
+		require.NoError(t, Add([]string{"CHANGELOG.md"}))
+		require.NoError(t, Commit("v1.1.0", CommitOptions{}))
+		require.NoError(t, CheckoutBranch("release"))
+		require.NoError(t, Merge("master", "Merge master into release, release: v1.1.0", CommitOptions{}))
+		require.NoError(t, Tag("1.1.0", TagOptions{}))
+		require.NoError(t, util.WriteFile(fs, "README.md", []byte("changed"), 0644))
+
+		undone, err := RestoreState(state)
+		require.NoError(t, err)
+		require.Equal(t, 4, len(undone))
+		require.Contains(t, undone[0], "reset branch release from ")
+		require.Equal(t, "checked out branch master (instead of release)", undone[1])
+		require.Contains(t, undone[2], "reset branch master from ")
+		require.Equal(t, "deleted tag 1.1.0", undone[3])
+
+		restored, err := CaptureState("master", "release")
+		require.NoError(t, err)
+		require.Equal(t, state, restored)
+
+		changed, err := AreUncommitedChanges()
+		require.NoError(t, err)
+		require.Equal(t, false, changed)
+	}
+
+	t.Log("discard uncommitted changes")
+	{
+		require.NoError(t, util.WriteFile(fs, "README.md", []byte("changed"), 0644))
+
+		undone, err := RestoreState(state)
+		require.NoError(t, err)
+		require.Equal(t, []string{"discarded uncommitted changes on branch master"}, undone)
+
+		changed, err := AreUncommitedChanges()
+		require.NoError(t, err)
+		require.Equal(t, false, changed)
+	}
+}
@@ -0,0 +1,77 @@
+package git
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/bitrise-tools/releaseman/versioning"
+)
+
+//=======================================
+// Consts
+//=======================================
+
+// DefaultTagFormat tags the releases with the bare version.
+const DefaultTagFormat TagFormat = "{{.Version}}"
+
+var tagFormatVersionRegexp = regexp.MustCompile(`\{\{\s*\.Version\s*\}\}`)
+
+//=======================================
+// Models
+//=======================================
+
+// TagFormat is the template of the release tags, e.g. v{{.Version}} or mobile/{{.Version}}.
+// It is used both to create the tag of a version and to parse the version of a tag.
+// Empty format means DefaultTagFormat.
+type TagFormat string
+
+//=======================================
+// Main
+//=======================================
+
+func (format TagFormat) parts() (string, string) {
+	if format == "" {
+		format = DefaultTagFormat
+	}
+
+	loc := tagFormatVersionRegexp.FindStringIndex(string(format))
+	if loc == nil {
+		return string(format), ""
+	}
+	return string(format)[:loc[0]], string(format)[loc[1]:]
+}
+
+// Validate ...
+func (format TagFormat) Validate() error {
+	if format == "" {
+		return nil
+	}
+	if count := len(tagFormatVersionRegexp.FindAllString(string(format), -1)); count != 1 {
+		return fmt.Errorf("Invalid tag format (%s): it has to contain {{.Version}} exactly once", format)
+	}
+	if strings.Contains(tagFormatVersionRegexp.ReplaceAllString(string(format), ""), "{{") {
+		return fmt.Errorf("Invalid tag format (%s): only {{.Version}} is supported", format)
+	}
+	return nil
+}
+
+// Tag returns the tag of the version.
+func (format TagFormat) Tag(version string) string {
+	prefix, suffix := format.parts()
+	return prefix + version + suffix
+}
+
+// Version returns the version of the tag, false if the tag does not match the format or the version scheme.
+func (format TagFormat) Version(tag string, scheme versioning.Scheme) (string, bool) {
+	prefix, suffix := format.parts()
+	if !strings.HasPrefix(tag, prefix) || !strings.HasSuffix(tag, suffix) || len(tag) < len(prefix)+len(suffix) {
+		return "", false
+	}
+
+	versionStr := strings.TrimSuffix(strings.TrimPrefix(tag, prefix), suffix)
+	if err := scheme.Validate(versionStr); err != nil {
+		return "", false
+	}
+	return versionStr, true
+}
@@ -0,0 +1,65 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/bitrise-tools/releaseman/versioning"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTagFormat(t *testing.T) {
+	t.Log("default format")
+	{
+		format := TagFormat("")
+		require.NoError(t, format.Validate())
+		require.Equal(t, "1.2.3", format.Tag("1.2.3"))
+
+		version, ok := format.Version("1.2.3", versioning.SemVer{})
+		require.Equal(t, true, ok)
+		require.Equal(t, "1.2.3", version)
+
+		_, ok = format.Version("mobile/1.2.3", versioning.SemVer{})
+		require.Equal(t, false, ok)
+	}
+
+	t.Log("prefix")
+	{
+		format := TagFormat("v{{.Version}}")
+		require.NoError(t, format.Validate())
+		require.Equal(t, "v1.2.3", format.Tag("1.2.3"))
+
+		version, ok := format.Version("v1.2.3", versioning.SemVer{})
+		require.Equal(t, true, ok)
+		require.Equal(t, "1.2.3", version)
+
+		_, ok = format.Version("1.2.3", versioning.SemVer{})
+		require.Equal(t, false, ok)
+		_, ok = format.Version("v", versioning.SemVer{})
+		require.Equal(t, false, ok)
+		_, ok = format.Version("vnext", versioning.SemVer{})
+		require.Equal(t, false, ok)
+	}
+
+	t.Log("namespace and suffix")
+	{
+		format := TagFormat("mobile/{{ .Version }}-release")
+		require.NoError(t, format.Validate())
+		require.Equal(t, "mobile/1.2.3-release", format.Tag("1.2.3"))
+
+		version, ok := format.Version("mobile/1.2.3-release", versioning.SemVer{})
+		require.Equal(t, true, ok)
+		require.Equal(t, "1.2.3", version)
+
+		_, ok = format.Version("web/1.2.3-release", versioning.SemVer{})
+		require.Equal(t, false, ok)
+		_, ok = format.Version("mobile/1.2.3", versioning.SemVer{})
+		require.Equal(t, false, ok)
+	}
+
+	t.Log("invalid formats")
+	{
+		require.Error(t, TagFormat("v1").Validate())
+		require.Error(t, TagFormat("{{.Version}}-{{.Version}}").Validate())
+		require.Error(t, TagFormat("{{.Branch}}/{{.Version}}").Validate())
+	}
+}
@@ -0,0 +1,62 @@
+package releaseman
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"text/template"
+
+	"github.com/bitrise-tools/releaseman/git"
+)
+
+//=======================================
+// Consts
+//=======================================
+
+// SemVer build metadata: dot separated, non empty identifiers of [0-9A-Za-z-]
+var buildMetadataRegexp = regexp.MustCompile(`^[0-9A-Za-z-]+(\.[0-9A-Za-z-]+)*$`)
+
+//=======================================
+// Models
+//=======================================
+
+// BuildMetadataModel is the model of the build metadata template.
+type BuildMetadataModel struct {
+	Commit      string
+	ShortCommit string
+	Date        string
+}
+
+//=======================================
+// Main
+//=======================================
+
+// RenderBuildMetadata renders the build metadata of the version from the released commit.
+func RenderBuildMetadata(templateStr string, commit git.CommitModel) (string, error) {
+	shortCommit := commit.Hash
+	if len(shortCommit) > 7 {
+		shortCommit = shortCommit[:7]
+	}
+
+	model := BuildMetadataModel{
+		Commit:      commit.Hash,
+		ShortCommit: shortCommit,
+		Date:        commit.Date.Format("20060102"),
+	}
+
+	metadataTemplate, err := template.New("build_metadata").Parse(templateStr)
+	if err != nil {
+		return "", fmt.Errorf("Failed to parse build metadata template, error: %s", err)
+	}
+
+	var metadataBytes bytes.Buffer
+	if err := metadataTemplate.Execute(&metadataBytes, model); err != nil {
+		return "", fmt.Errorf("Failed to execute build metadata template, error: %s", err)
+	}
+
+	metadata := metadataBytes.String()
+	if !buildMetadataRegexp.MatchString(metadata) {
+		return "", fmt.Errorf("Invalid build metadata (%s): only dot separated alphanumerics and hyphens are allowed", metadata)
+	}
+	return metadata, nil
+}
@@ -0,0 +1,30 @@
+package releaseman
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bitrise-tools/releaseman/git"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderBuildMetadata(t *testing.T) {
+	commit := git.CommitModel{
+		Hash: "85d8658733f73ae6d5407e8e4c2b81a5f2ed016c",
+		Date: time.Date(2016, time.February, 3, 11, 24, 23, 0, time.UTC),
+	}
+
+	metadata, err := RenderBuildMetadata("{{.ShortCommit}}", commit)
+	require.NoError(t, err)
+	require.Equal(t, "85d8658", metadata)
+
+	metadata, err = RenderBuildMetadata("build.{{.Date}}.{{.ShortCommit}}", commit)
+	require.NoError(t, err)
+	require.Equal(t, "build.20160203.85d8658", metadata)
+
+	_, err = RenderBuildMetadata("{{.Branch}}", commit)
+	require.Error(t, err)
+
+	_, err = RenderBuildMetadata("feature/x", commit)
+	require.Error(t, err)
+}
@@ -2,14 +2,12 @@ package releaseman
 
 import (
 	"bytes"
-	"errors"
 	"fmt"
 	"strings"
 	"text/template"
 	"time"
 
 	log "github.com/Sirupsen/logrus"
-	"github.com/bitrise-io/go-utils/fileutil"
 	"github.com/bitrise-tools/releaseman/git"
 )
 
@@ -32,6 +30,31 @@ const ChangelogContentTemplate = `{{range .ContentItems}}### {{.EndTaggedCommit.
 {{end}}
 {{end}}`
 
+// ChangelogSectionsContentTemplate ...
+const ChangelogSectionsContentTemplate = `{{range .ContentItems}}### {{.EndTaggedCommit.Tag}} ({{.EndTaggedCommit.Date.Format "2006 Jan 02"}})
+
+{{range .Sections}}#### {{.Title}}
+
+{{range .Commits}}* [{{firstChars .Hash 7}}] {{if .Scope}}**{{.Scope}}:** {{end}}{{.Description}}
+{{end}}
+{{end}}{{end}}`
+
+// DefaultChangelogSections ...
+var DefaultChangelogSections = []ChangelogSection{
+	ChangelogSection{
+		Title:    "Breaking Changes",
+		Breaking: true,
+	},
+	ChangelogSection{
+		Title: "Features",
+		Types: []string{"feat"},
+	},
+	ChangelogSection{
+		Title: "Bug Fixes",
+		Types: []string{"fix"},
+	},
+}
+
 var changelogTemplateFuncMap = template.FuncMap{
 	"firstChars": func(str string, length int) string {
 		if len(str) < length {
@@ -46,11 +69,18 @@ var changelogTemplateFuncMap = template.FuncMap{
 // Models
 //=======================================
 
+// ChangelogSectionModel ...
+type ChangelogSectionModel struct {
+	Title   string
+	Commits []git.CommitModel
+}
+
 // ChangelogContentItemModel ...
 type ChangelogContentItemModel struct {
 	StartTaggedCommit git.CommitModel
 	EndTaggedCommit   git.CommitModel
 	Commits           []git.CommitModel
+	Sections          []ChangelogSectionModel
 }
 
 // ChangelogModel ..
@@ -64,25 +94,126 @@ type ChangelogModel struct {
 // Utility
 //=======================================
 
-func commitsBetween(startDate *time.Time, endDate *time.Time, commits []git.CommitModel) []git.CommitModel {
-	relevantCommits := []git.CommitModel{}
-	isRelevantCommit := false
+// commitsBetween returns the commits reachable from endCommit but not from startCommit,
+// the same set git selects for the startCommit..endCommit revision range.
+// nil startCommit means no lower bound, nil endCommit means every commit in commits is a candidate.
+// The start and end commits are walked even if they are not in commits (e.g. the merge commits of the release branch).
+func commitsBetween(startCommit *git.CommitModel, endCommit *git.CommitModel, commits []git.CommitModel) []git.CommitModel {
+	commitsByHash := map[string]git.CommitModel{}
+	for _, boundCommit := range []*git.CommitModel{startCommit, endCommit} {
+		if boundCommit != nil {
+			commitsByHash[boundCommit.Hash] = *boundCommit
+		}
+	}
+	for _, commit := range commits {
+		commitsByHash[commit.Hash] = commit
+	}
 
+	excludedHashes := map[string]bool{}
+	if startCommit != nil {
+		excludedHashes = reachableHashes(startCommit.Hash, commitsByHash)
+	}
+
+	var includedHashes map[string]bool
+	if endCommit != nil {
+		includedHashes = reachableHashes(endCommit.Hash, commitsByHash)
+	}
+
+	relevantCommits := []git.CommitModel{}
 	for _, commit := range commits {
-		if !isRelevantCommit && (startDate == nil || (*startDate).Sub(commit.Date) < 0) {
-			isRelevantCommit = true
+		if excludedHashes[commit.Hash] {
+			continue
 		}
+		if includedHashes != nil && !includedHashes[commit.Hash] {
+			continue
+		}
+		relevantCommits = append(relevantCommits, commit)
+	}
+
+	return relevantCommits
+}
+
+// reachableHashes walks the parents of the given commit, commits missing from commitsByHash end the walk.
+func reachableHashes(hash string, commitsByHash map[string]git.CommitModel) map[string]bool {
+	reachable := map[string]bool{}
 
-		if isRelevantCommit && endDate != nil && (*endDate).Sub(commit.Date) < 0 {
-			return relevantCommits
+	queue := []string{hash}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		if current == "" || reachable[current] {
+			continue
 		}
+		reachable[current] = true
 
-		if isRelevantCommit {
-			relevantCommits = append([]git.CommitModel{commit}, relevantCommits...)
+		if commit, ok := commitsByHash[current]; ok {
+			queue = append(queue, commit.Parents...)
 		}
 	}
 
-	return relevantCommits
+	return reachable
+}
+
+func (section ChangelogSection) matches(commit git.CommitModel) bool {
+	if section.Breaking && !commit.Breaking {
+		return false
+	}
+	if len(section.Types) == 0 {
+		return true
+	}
+	for _, commitType := range section.Types {
+		if commitType == commit.Type {
+			return true
+		}
+	}
+	return false
+}
+
+// groupCommits lists every commit in the first section it matches, empty sections are omitted.
+func groupCommits(commits []git.CommitModel, sections []ChangelogSection) []ChangelogSectionModel {
+	groups := make([]ChangelogSectionModel, len(sections))
+	for idx, section := range sections {
+		groups[idx] = ChangelogSectionModel{
+			Title:   section.Title,
+			Commits: []git.CommitModel{},
+		}
+	}
+
+	for _, commit := range commits {
+		for idx, section := range sections {
+			if section.matches(commit) {
+				groups[idx].Commits = append(groups[idx].Commits, commit)
+				break
+			}
+		}
+	}
+
+	nonEmptyGroups := []ChangelogSectionModel{}
+	for _, group := range groups {
+		if len(group.Commits) > 0 {
+			nonEmptyGroups = append(nonEmptyGroups, group)
+		}
+	}
+	return nonEmptyGroups
+}
+
+// groupContentItems filters and groups the commits of the content items into sections, the items are reversed to start with the newest.
+func groupContentItems(contentItems []ChangelogContentItemModel, config Config) []ChangelogContentItemModel {
+	sections := DefaultChangelogSections
+	if config.Changelog.Format == KeepAChangelogFormat {
+		sections = KeepAChangelogSections
+	}
+	if len(config.Changelog.Sections) > 0 {
+		sections = config.Changelog.Sections
+	}
+
+	for idx, contentItem := range contentItems {
+		contentItems[idx].Commits = config.FilterCommits(contentItem.Commits)
+		contentItems[idx].Sections = groupCommits(contentItems[idx].Commits, sections)
+	}
+
+	return reversedSections(contentItems)
 }
 
 func reversedSections(sections []ChangelogContentItemModel) []ChangelogContentItemModel {
@@ -93,7 +224,13 @@ func reversedSections(sections []ChangelogContentItemModel) []ChangelogContentIt
 	return reversed
 }
 
-func generateChangelogContent(commits, taggedCommits []git.CommitModel, version string) ChangelogModel {
+// generateChangelogContent groups the commits by the tagged commits, the commits after the last tagged commit
+// belong to the new version, which will be tagged with the release tag.
+// The commits are filtered by the config only after the grouping, as the grouping walks the commit parents.
+func generateChangelogContent(commits, taggedCommits []git.CommitModel, config Config) ChangelogModel {
+	version := config.Release.Version
+	tag := config.ReleaseTag()
+
 	content := ChangelogModel{
 		ContentItems: []ChangelogContentItemModel{},
 		Version:      version,
@@ -107,7 +244,7 @@ func generateChangelogContent(commits, taggedCommits []git.CommitModel, version
 				startTaggedCommit := taggedCommits[i]
 				endTaggedCommit := taggedCommits[i+1]
 
-				relevantCommits := commitsBetween(&(startTaggedCommit.Date), &(endTaggedCommit.Date), commits)
+				relevantCommits := commitsBetween(&startTaggedCommit, &endTaggedCommit, commits)
 
 				contentItem := ChangelogContentItemModel{
 					StartTaggedCommit: startTaggedCommit,
@@ -119,13 +256,14 @@ func generateChangelogContent(commits, taggedCommits []git.CommitModel, version
 		}
 
 		// Commits between last tag and current state
-		relevantCommits := commitsBetween(&(taggedCommits[len(taggedCommits)-1].Date), nil, commits)
+		relevantCommits := commitsBetween(&(taggedCommits[len(taggedCommits)-1]), nil, commits)
 
 		contentItem := ChangelogContentItemModel{
 			StartTaggedCommit: taggedCommits[len(taggedCommits)-1],
 			EndTaggedCommit: git.CommitModel{
-				Tag:  version,
-				Date: time.Now(),
+				Tag:     tag,
+				Version: version,
+				Date:    time.Now(),
 			},
 			Commits: relevantCommits,
 		}
@@ -136,99 +274,133 @@ func generateChangelogContent(commits, taggedCommits []git.CommitModel, version
 		contentItem := ChangelogContentItemModel{
 			StartTaggedCommit: git.CommitModel{},
 			EndTaggedCommit: git.CommitModel{
-				Tag:  version,
-				Date: time.Now(),
+				Tag:     tag,
+				Version: version,
+				Date:    time.Now(),
 			},
 			Commits: relevantCommits,
 		}
 		content.ContentItems = append(content.ContentItems, contentItem)
 	}
 
-	content.ContentItems = reversedSections(content.ContentItems)
+	content.ContentItems = groupContentItems(content.ContentItems, config)
 
 	return content
 }
 
-func parseChangelog(changelog string) (string, error) {
-	layoutLines := strings.Split(changelog, "\n")
-
-	log.Debug("")
-	log.Debugf("layoutLines: %v", layoutLines)
-
-	contentStartIdx := -1
-	contentEndIdx := -1
+// generateChangelogHistory groups every commit by the tagged commits: every tagged commit is a release,
+// the first release lists the commits since the initial commit.
+// The commits after the last tagged commit are listed as unreleased (with empty version) in the keepachangelog format only.
+func generateChangelogHistory(commits, taggedCommits []git.CommitModel, config Config) ChangelogModel {
+	content := ChangelogModel{
+		ContentItems: []ChangelogContentItemModel{},
+		Version:      config.Release.Version,
+		CurrentDate:  time.Now(),
+	}
 
-	headerStr := ""
-	for idx, line := range layoutLines {
-		if headerStr == "" {
-			headerStr = line
-		} else {
-			headerStr += fmt.Sprintf("\n%s", line)
+	for idx, taggedCommit := range taggedCommits {
+		var startTaggedCommitPtr *git.CommitModel
+		startTaggedCommit := git.CommitModel{}
+		if idx > 0 {
+			startTaggedCommitPtr = &(taggedCommits[idx-1])
+			startTaggedCommit = taggedCommits[idx-1]
 		}
+		endTaggedCommit := taggedCommit
+
+		content.ContentItems = append(content.ContentItems, ChangelogContentItemModel{
+			StartTaggedCommit: startTaggedCommit,
+			EndTaggedCommit:   endTaggedCommit,
+			Commits:           commitsBetween(startTaggedCommitPtr, &endTaggedCommit, commits),
+		})
+		content.Version = taggedCommit.Version
+	}
 
-		if line == separator {
-			if headerStr != "" {
-				headerStr += "\n"
-			}
-
-			contentStartIdx = idx + 1
-			break
+	if config.Changelog.Format == KeepAChangelogFormat {
+		contentItem := ChangelogContentItemModel{
+			StartTaggedCommit: git.CommitModel{},
+			EndTaggedCommit:   git.CommitModel{},
+			Commits:           commitsBetween(nil, nil, commits),
+		}
+		if len(taggedCommits) > 0 {
+			contentItem.StartTaggedCommit = taggedCommits[len(taggedCommits)-1]
+			contentItem.Commits = commitsBetween(&(taggedCommits[len(taggedCommits)-1]), nil, commits)
 		}
+		content.ContentItems = append(content.ContentItems, contentItem)
 	}
 
-	log.Debug("")
-	log.Debugf("contentStartIdx: %d", contentStartIdx)
+	content.ContentItems = groupContentItems(content.ContentItems, config)
 
-	footerStr := ""
-	for i := (len(layoutLines) - 1); i >= 0; i-- {
-		line := layoutLines[i]
-
-		if footerStr == "" {
-			footerStr = line
-		} else {
-			footerStr = fmt.Sprintf("%s\n%s", line, footerStr)
-		}
+	return content
+}
 
-		if line == separator {
-			contentEndIdx = i - 1
-			break
-		}
+// renderChangelogContent renders the content items of the changelog with the content template of the config.
+func renderChangelogContent(changelog ChangelogModel, config Config) (string, error) {
+	changelogContentTemplateStr := ChangelogContentTemplate
+	if len(config.Changelog.Sections) > 0 {
+		changelogContentTemplateStr = ChangelogSectionsContentTemplate
+	}
+	if config.Changelog.ContentTemplate != "" {
+		changelogContentTemplateStr = config.Changelog.ContentTemplate
 	}
 
-	log.Debug("")
-	log.Debugf("contentEndIdx: %d", contentEndIdx)
+	contentTemplate := template.New("changelog_content").Funcs(changelogTemplateFuncMap)
+	contentTemplate, err := contentTemplate.Parse(changelogContentTemplateStr)
+	if err != nil {
+		return "", fmt.Errorf("Failed to parse content template, error: %s", err)
+	}
 
-	contentStr := ""
-	if contentStartIdx > -1 && contentEndIdx > -1 {
-		for i := contentStartIdx; i <= contentEndIdx; i++ {
-			line := layoutLines[i]
-			if contentStr == "" {
-				contentStr = line
-			} else {
-				contentStr += fmt.Sprintf("\n%s", line)
-			}
-		}
-	} else {
-		return "", errors.New("failed to parse footer and header")
+	var contentBytes bytes.Buffer
+	err = contentTemplate.Execute(&contentBytes, changelog)
+	if err != nil {
+		return "", fmt.Errorf("Failed to execute template, error: %s", err)
 	}
+	contentStr := contentBytes.String()
 
-	log.Debug("")
-	log.Debug("contentStr: %s", contentStr)
+	contentSplit := strings.Split(contentStr, "\n")
+	if len(contentSplit) > 0 {
+		contentSplit = contentSplit[0 : len(contentSplit)-1]
+		contentStr = strings.Join(contentSplit, "\n")
+	}
 
 	return contentStr, nil
 }
 
+// writeChangelogData writes the changelog data next to the changelog, if the data path is set.
+func writeChangelogData(changelog ChangelogModel, config Config, appendToExisting bool) error {
+	if config.Changelog.DataPath == "" {
+		return nil
+	}
+	if err := WriteChangelogData(changelog, config, appendToExisting); err != nil {
+		return fmt.Errorf("Failed to write changelog data, error: %s", err)
+	}
+	return nil
+}
+
 //=======================================
 // Main
 //=======================================
 
 // WriteChangelog ...
-func WriteChangelog(commits, taggedCommits []git.CommitModel, config Config, append bool) error {
-	newChangelog := generateChangelogContent(commits, taggedCommits, config.Release.Version)
+func WriteChangelog(commits, taggedCommits []git.CommitModel, config Config, appendToExisting bool) error {
+	return writeChangelog(generateChangelogContent(commits, taggedCommits, config), config, appendToExisting)
+}
+
+// RebuildChangelog regenerates the whole changelog from every tagged commit, with the current templates.
+func RebuildChangelog(commits, taggedCommits []git.CommitModel, config Config) error {
+	return writeChangelog(generateChangelogHistory(commits, taggedCommits, config), config, false)
+}
+
+// writeChangelog writes the changelog of the model, if appendToExisting is true, the model is merged into the existing changelog.
+func writeChangelog(newChangelog ChangelogModel, config Config, appendToExisting bool) error {
+	if config.Changelog.Format == KeepAChangelogFormat {
+		if err := writeKeepAChangelog(newChangelog, config, appendToExisting); err != nil {
+			return err
+		}
+		return writeChangelogData(newChangelog, config, appendToExisting)
+	}
 
 	headerStr := ""
 	footerStr := ""
-	contentStr := ""
 
 	//
 	// Generate changelog header
@@ -247,13 +419,13 @@ func WriteChangelog(commits, taggedCommits []git.CommitModel, config Config, app
 		headerTemplate := template.New("changelog_header").Funcs(changelogTemplateFuncMap)
 		headerTemplate, err := headerTemplate.Parse(config.Changelog.HeaderTemplate)
 		if err != nil {
-			log.Fatalf("Failed to parse header template, error: %#v", err)
+			return fmt.Errorf("Failed to parse header template, error: %s", err)
 		}
 
 		var headerBytes bytes.Buffer
 		err = headerTemplate.Execute(&headerBytes, newChangelog)
 		if err != nil {
-			log.Fatalf("Failed to execute layout template, error: %#v", err)
+			return fmt.Errorf("Failed to execute layout template, error: %s", err)
 		}
 		headerStr = headerBytes.String()
 		headerStr += "\n\n" + separator + "\n"
@@ -264,103 +436,95 @@ func WriteChangelog(commits, taggedCommits []git.CommitModel, config Config, app
 		footerTemplate := template.New("changelog_footer").Funcs(changelogTemplateFuncMap)
 		footerTemplate, err := footerTemplate.Parse(config.Changelog.FooterTemplate)
 		if err != nil {
-			log.Fatalf("Failed to parse footer template, error: %#v", err)
+			return fmt.Errorf("Failed to parse footer template, error: %s", err)
 		}
 
 		var footerBytes bytes.Buffer
 		err = footerTemplate.Execute(&footerBytes, newChangelog)
 		if err != nil {
-			log.Fatalf("Failed to execute footer template, error: %#v", err)
+			return fmt.Errorf("Failed to execute footer template, error: %s", err)
 		}
 		footerStr = footerBytes.String()
 		footerStr = separator + "\n\n" + footerStr
 	}
 
 	log.Debug()
-	log.Debug("Layout header: %s", headerStr)
-	log.Debug("Layout footer: %s", footerStr)
+	log.Debugf("Layout header: %s", headerStr)
+	log.Debugf("Layout footer: %s", footerStr)
 
 	//
 	// Generate changelog content
-	changelogContentTemplateStr := ChangelogContentTemplate
-	if config.Changelog.ContentTemplate != "" {
-		changelogContentTemplateStr = config.Changelog.ContentTemplate
-	}
-
-	contentTemplate := template.New("changelog_content").Funcs(changelogTemplateFuncMap)
-	contentTemplate, err := contentTemplate.Parse(changelogContentTemplateStr)
-	if err != nil {
-		log.Fatalf("Failed to parse content template, error: %#v", err)
-	}
-
-	var newContentBytes bytes.Buffer
-	err = contentTemplate.Execute(&newContentBytes, newChangelog)
+	newContentStr, err := renderChangelogContent(newChangelog, config)
 	if err != nil {
-		log.Fatalf("Failed to execute template, error: %#v", err)
-	}
-	newContentStr := newContentBytes.String()
-
-	newContentSplit := strings.Split(newContentStr, "\n")
-	if len(newContentSplit) > 0 {
-		newContentSplit = newContentSplit[0 : len(newContentSplit)-1]
-		newContentStr = strings.Join(newContentSplit, "\n")
+		return err
 	}
 
 	log.Debug()
 	log.Debug("Content:")
 	for _, line := range strings.Split(newContentStr, "\n") {
-		log.Debug("%s", line)
+		log.Debugf("%s", line)
 	}
 
 	// Join header and content
-	if append {
+	changelogStr := ""
+	if appendToExisting {
 
 		log.Debug()
-		log.Debug("Previous changelog exist, append new conent")
+		log.Debug("Previous changelog exist, merge new content")
 
-		prevChangelogStr, err := fileutil.ReadStringFromFile(config.Changelog.Path)
+		prevChangelogBytes, err := readFile(config.Changelog.Path)
 		if err != nil {
 			return err
 		}
+		prevChangelogStr := string(prevChangelogBytes)
 
-		prevContentStr := ""
-		if config.Changelog.HeaderTemplate != "" && config.Changelog.FooterTemplate != "" {
-			tmpPrevContentStr, err := parseChangelog(prevChangelogStr)
-			if err != nil {
-				log.Warnf("Failed to parse previous changelog: %s", err)
-			} else {
-				prevContentStr = tmpPrevContentStr
-			}
-		} else {
-			prevContentStr = prevChangelogStr
-		}
+		document := ParseChangelogDocument(prevChangelogStr)
+		newDocument := ParseChangelogDocument(newContentStr)
 
 		log.Debug()
-		log.Debug("Prev content:")
-
-		for _, line := range strings.Split(prevContentStr, "\n") {
-			log.Debugf("%s", line)
-		}
+		log.Debugf("Prev releases: %d, new releases: %d", len(document.Releases), len(newDocument.Releases))
 
-		contentStr = fmt.Sprintf("%s\n%s", newContentStr, prevContentStr)
+		if len(document.Releases) == 0 || len(newDocument.Releases) == 0 {
+			log.Warnf("No release sections found in the changelog, the new content is prepended")
 
-		log.Debug()
-		log.Debug("Merged content:")
+			changelogStr = fmt.Sprintf("%s\n%s", newContentStr, prevChangelogStr)
+		} else {
+			scheme, err := config.VersionScheme()
+			if err != nil {
+				return err
+			}
 
-		contentSplits := strings.Split(contentStr, "\n")
+			for idx := len(newDocument.Releases) - 1; idx >= 0; idx-- {
+				if err := document.Upsert(newDocument.Releases[idx], scheme); err != nil {
+					return err
+				}
+			}
+			if config.Changelog.HeaderTemplate != "" {
+				document.Header = strings.TrimSpace(headerStr)
+			}
+			if config.Changelog.FooterTemplate != "" {
+				document.Footer = strings.TrimSpace(footerStr)
+			}
 
-		for _, line := range contentSplits {
-			log.Debugf("%s", line)
+			changelogStr = document.String()
 		}
 	} else {
 
 		log.Debug()
 		log.Debug("NO previous changelog exist")
 
-		contentStr = newContentStr
+		changelogStr = headerStr + "\n" + newContentStr + "\n" + footerStr
 	}
 
-	changelogStr := headerStr + "\n" + contentStr + "\n" + footerStr
+	log.Debug()
+	log.Debug("Changelog:")
+	for _, line := range strings.Split(changelogStr, "\n") {
+		log.Debugf("%s", line)
+	}
+
+	if err := writeFile(config.Changelog.Path, changelogStr, 0); err != nil {
+		return err
+	}
 
-	return fileutil.WriteStringToFile(config.Changelog.Path, changelogStr)
+	return writeChangelogData(newChangelog, config, appendToExisting)
 }
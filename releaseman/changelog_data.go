@@ -0,0 +1,229 @@
+package releaseman
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bitrise-tools/releaseman/git"
+	"gopkg.in/yaml.v2"
+)
+
+//=======================================
+// Consts
+//=======================================
+
+const (
+	// JSONChangelogData ...
+	JSONChangelogData = "json"
+	// YAMLChangelogData ...
+	YAMLChangelogData = "yaml"
+)
+
+// ChangelogDataSchemaVersion is the schema version of the changelog data documents,
+// it is incremented on every incompatible change of the ChangelogData types.
+const ChangelogDataSchemaVersion = 1
+
+// changelogDataDateFormat is RFC 3339
+const changelogDataDateFormat = time.RFC3339
+
+//=======================================
+// Models
+//=======================================
+
+// ChangelogData is the machine-readable changelog, the releases are ordered from the newest.
+type ChangelogData struct {
+	SchemaVersion int                    `json:"schema_version" yaml:"schema_version"`
+	Releases      []ChangelogReleaseData `json:"releases" yaml:"releases"`
+}
+
+// ChangelogReleaseData is a release: the commits since the previous release.
+type ChangelogReleaseData struct {
+	Version string `json:"version" yaml:"version"`
+	Tag     string `json:"tag" yaml:"tag"`
+	// Date is RFC 3339 formatted
+	Date        string                `json:"date" yaml:"date"`
+	PreviousTag string                `json:"previous_tag,omitempty" yaml:"previous_tag,omitempty"`
+	Commits     []ChangelogCommitData `json:"commits" yaml:"commits"`
+}
+
+// ChangelogCommitData is a commit of a release.
+type ChangelogCommitData struct {
+	Hash   string `json:"hash" yaml:"hash"`
+	Author string `json:"author" yaml:"author"`
+	// Date is RFC 3339 formatted
+	Date    string `json:"date" yaml:"date"`
+	Message string `json:"message" yaml:"message"`
+	// Type, Scope, Description and Breaking are the Conventional Commits fields
+	Type        string `json:"type,omitempty" yaml:"type,omitempty"`
+	Scope       string `json:"scope,omitempty" yaml:"scope,omitempty"`
+	Description string `json:"description" yaml:"description"`
+	Breaking    bool   `json:"breaking,omitempty" yaml:"breaking,omitempty"`
+	// Section is the title of the changelog section listing the commit, empty if no section lists it
+	Section string `json:"section,omitempty" yaml:"section,omitempty"`
+}
+
+//=======================================
+// Utility
+//=======================================
+
+func formatChangelogDataDate(date time.Time) string {
+	if date.IsZero() {
+		return ""
+	}
+	return date.Format(changelogDataDateFormat)
+}
+
+// changelogDataFormat returns the configured format, or the format detected from the file extension.
+func changelogDataFormat(changelog Changelog) (string, error) {
+	if changelog.DataFormat != "" {
+		if changelog.DataFormat != JSONChangelogData && changelog.DataFormat != YAMLChangelogData {
+			return "", fmt.Errorf("Invalid changelog data format (%s), available formats: %s, %s", changelog.DataFormat, JSONChangelogData, YAMLChangelogData)
+		}
+		return changelog.DataFormat, nil
+	}
+
+	switch strings.ToLower(filepath.Ext(changelog.DataPath)) {
+	case ".json":
+		return JSONChangelogData, nil
+	case ".yml", ".yaml":
+		return YAMLChangelogData, nil
+	}
+	return "", fmt.Errorf("Failed to detect the format of the changelog data (%s), set the data_format", changelog.DataPath)
+}
+
+func newChangelogCommitData(commit git.CommitModel, sections []ChangelogSectionModel) ChangelogCommitData {
+	section := ""
+	for _, sectionModel := range sections {
+		for _, sectionCommit := range sectionModel.Commits {
+			if sectionCommit.Hash == commit.Hash {
+				section = sectionModel.Title
+			}
+		}
+	}
+
+	return ChangelogCommitData{
+		Hash:        commit.Hash,
+		Author:      commit.Author,
+		Date:        formatChangelogDataDate(commit.Date),
+		Message:     commit.Message,
+		Type:        commit.Type,
+		Scope:       commit.Scope,
+		Description: commit.Description,
+		Breaking:    commit.Breaking,
+		Section:     section,
+	}
+}
+
+// mergeChangelogData prepends the new releases to the previous ones, replacing the previous releases with the same tag.
+func mergeChangelogData(prevData, newData ChangelogData) ChangelogData {
+	newTags := map[string]bool{}
+	for _, release := range newData.Releases {
+		newTags[release.Tag] = true
+	}
+
+	merged := ChangelogData{
+		SchemaVersion: ChangelogDataSchemaVersion,
+		Releases:      append([]ChangelogReleaseData{}, newData.Releases...),
+	}
+	for _, release := range prevData.Releases {
+		if !newTags[release.Tag] {
+			merged.Releases = append(merged.Releases, release)
+		}
+	}
+	return merged
+}
+
+func marshalChangelogData(data ChangelogData, format string) ([]byte, error) {
+	if format == YAMLChangelogData {
+		return yaml.Marshal(data)
+	}
+	bytes, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(bytes, '\n'), nil
+}
+
+func unmarshalChangelogData(bytes []byte, format string) (ChangelogData, error) {
+	data := ChangelogData{}
+	var err error
+	if format == YAMLChangelogData {
+		err = yaml.Unmarshal(bytes, &data)
+	} else {
+		err = json.Unmarshal(bytes, &data)
+	}
+	if err != nil {
+		return ChangelogData{}, err
+	}
+
+	if data.SchemaVersion > ChangelogDataSchemaVersion {
+		return ChangelogData{}, fmt.Errorf("Unsupported changelog data schema version (%d), the highest supported version is %d", data.SchemaVersion, ChangelogDataSchemaVersion)
+	}
+	return data, nil
+}
+
+//=======================================
+// Main
+//=======================================
+
+// NewChangelogData returns the machine-readable form of the changelog, the unreleased commits are not listed.
+func NewChangelogData(changelog ChangelogModel) ChangelogData {
+	data := ChangelogData{
+		SchemaVersion: ChangelogDataSchemaVersion,
+		Releases:      []ChangelogReleaseData{},
+	}
+
+	for _, contentItem := range changelog.ContentItems {
+		if contentItem.EndTaggedCommit.Version == "" {
+			continue
+		}
+
+		release := ChangelogReleaseData{
+			Version:     contentItem.EndTaggedCommit.Version,
+			Tag:         contentItem.EndTaggedCommit.Tag,
+			Date:        formatChangelogDataDate(contentItem.EndTaggedCommit.Date),
+			PreviousTag: contentItem.StartTaggedCommit.Tag,
+			Commits:     []ChangelogCommitData{},
+		}
+		for _, commit := range contentItem.Commits {
+			release.Commits = append(release.Commits, newChangelogCommitData(commit, contentItem.Sections))
+		}
+		data.Releases = append(data.Releases, release)
+	}
+
+	return data
+}
+
+// WriteChangelogData writes the changelog data document of the changelog to the data path,
+// if appendToExisting is true, the releases are prepended to the existing document.
+func WriteChangelogData(changelog ChangelogModel, config Config, appendToExisting bool) error {
+	format, err := changelogDataFormat(config.Changelog)
+	if err != nil {
+		return err
+	}
+
+	data := NewChangelogData(changelog)
+
+	if appendToExisting {
+		// the missing data file is created, e.g. when the data path is set in an existing project
+		if bytes, err := readFile(config.Changelog.DataPath); err == nil {
+			prevData, err := unmarshalChangelogData(bytes, format)
+			if err != nil {
+				return fmt.Errorf("Failed to parse changelog data (%s), error: %s", config.Changelog.DataPath, err)
+			}
+			data = mergeChangelogData(prevData, data)
+		} else if !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	bytes, err := marshalChangelogData(data, format)
+	if err != nil {
+		return err
+	}
+	return writeFile(config.Changelog.DataPath, string(bytes), 0)
+}
@@ -0,0 +1,131 @@
+package releaseman
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bitrise-tools/releaseman/git"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangelogData(t *testing.T) {
+	date := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	taggedCommits := []git.CommitModel{
+		git.CommitModel{Hash: "1", Tag: "1.0.0", Version: "1.0.0", Date: date},
+	}
+	commits := []git.CommitModel{
+		git.CommitModel{Hash: "3", Parents: []string{"2"}, Author: "bitrise", Date: date, Message: "fix(cli): exit code", Type: "fix", Scope: "cli", Description: "exit code"},
+		git.CommitModel{Hash: "2", Parents: []string{"1"}, Author: "bitrise", Date: date, Message: "Update README", Description: "Update README"},
+		taggedCommits[0],
+	}
+	config := Config{Release: Release{Version: "1.1.0"}}
+
+	data := NewChangelogData(generateChangelogContent(commits, taggedCommits, config))
+	require.Equal(t, ChangelogDataSchemaVersion, data.SchemaVersion)
+	require.Equal(t, 1, len(data.Releases))
+	require.Equal(t, "1.1.0", data.Releases[0].Version)
+	require.Equal(t, "1.0.0", data.Releases[0].PreviousTag)
+	require.Equal(t, ChangelogCommitData{
+		Hash:        "3",
+		Author:      "bitrise",
+		Date:        "2026-10-16T12:00:00Z",
+		Message:     "fix(cli): exit code",
+		Type:        "fix",
+		Scope:       "cli",
+		Description: "exit code",
+		Section:     "Bug Fixes",
+	}, data.Releases[0].Commits[0])
+	require.Equal(t, "", data.Releases[0].Commits[1].Section)
+
+	tmpDir, err := ioutil.TempDir("", "releaseman")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, os.RemoveAll(tmpDir))
+	}()
+
+	for _, dataPath := range []string{"CHANGELOG.json", "CHANGELOG.yml"} {
+		t.Logf("write %s", dataPath)
+		{
+			config := config
+			config.Changelog.Path = filepath.Join(tmpDir, "CHANGELOG.md")
+			config.Changelog.DataPath = filepath.Join(tmpDir, dataPath)
+			require.NoError(t, WriteChangelog(commits, taggedCommits, config, false))
+
+			format, err := changelogDataFormat(config.Changelog)
+			require.NoError(t, err)
+			bytes, err := ioutil.ReadFile(config.Changelog.DataPath)
+			require.NoError(t, err)
+			written, err := unmarshalChangelogData(bytes, format)
+			require.NoError(t, err)
+			require.Equal(t, data.Releases[0].Commits, written.Releases[0].Commits)
+			require.Equal(t, "1.1.0", written.Releases[0].Version)
+
+			// the next release is prepended, the rewritten release is replaced
+			config.Release.Version = "1.2.0"
+			require.NoError(t, WriteChangelog(commits[:1], []git.CommitModel{commits[1]}, config, true))
+			config.Release.Version = "1.1.0"
+			require.NoError(t, WriteChangelog(commits, taggedCommits, config, true))
+
+			bytes, err = ioutil.ReadFile(config.Changelog.DataPath)
+			require.NoError(t, err)
+			written, err = unmarshalChangelogData(bytes, format)
+			require.NoError(t, err)
+			require.Equal(t, 2, len(written.Releases))
+			require.Equal(t, "1.1.0", written.Releases[0].Version)
+			require.Equal(t, "1.2.0", written.Releases[1].Version)
+		}
+	}
+
+	t.Log("the dry run merges into the changelogs written by the dry run")
+	{
+		IsDryRun = true
+		defer func() {
+			IsDryRun = false
+			dryRunChangedFiles = []string{}
+			dryRunContents = map[string]string{}
+		}()
+
+		config := config
+		config.Changelog.Path = filepath.Join(tmpDir, "DRY_RUN.md")
+		config.Changelog.DataPath = filepath.Join(tmpDir, "DRY_RUN.json")
+		require.NoError(t, WriteChangelog(commits, taggedCommits, config, false))
+		config.Release.Version = "1.2.0"
+		require.NoError(t, WriteChangelog(commits[:1], []git.CommitModel{commits[1]}, config, true))
+
+		changelogBytes, err := readFile(config.Changelog.Path)
+		require.NoError(t, err)
+		require.Contains(t, string(changelogBytes), "1.2.0")
+		require.Contains(t, string(changelogBytes), "1.1.0")
+
+		bytes, err := readFile(config.Changelog.DataPath)
+		require.NoError(t, err)
+		written, err := unmarshalChangelogData(bytes, JSONChangelogData)
+		require.NoError(t, err)
+		require.Equal(t, 2, len(written.Releases))
+
+		_, err = os.Stat(config.Changelog.DataPath)
+		require.True(t, os.IsNotExist(err))
+	}
+
+	t.Log("format")
+	{
+		_, err := changelogDataFormat(Changelog{DataPath: "releases.txt"})
+		require.Error(t, err)
+
+		format, err := changelogDataFormat(Changelog{DataPath: "releases.txt", DataFormat: YAMLChangelogData})
+		require.NoError(t, err)
+		require.Equal(t, YAMLChangelogData, format)
+
+		_, err = changelogDataFormat(Changelog{DataPath: "releases.xml", DataFormat: "xml"})
+		require.Error(t, err)
+	}
+
+	t.Log("unsupported schema version")
+	{
+		_, err := unmarshalChangelogData([]byte(`{"schema_version": 2, "releases": []}`), JSONChangelogData)
+		require.Error(t, err)
+	}
+}
@@ -0,0 +1,288 @@
+package releaseman
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/bitrise-tools/releaseman/versioning"
+)
+
+//=======================================
+// Consts
+//=======================================
+
+var (
+	markdownHeadingRegexp        = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	markdownFenceRegexp          = regexp.MustCompile("^\\s*(```|~~~)")
+	markdownThematicBreakRegexp  = regexp.MustCompile(`^\s*(-{3,}|\*{3,}|_{3,})\s*$`)
+	markdownLinkDefinitionRegexp = regexp.MustCompile(`^\s*\[([^\]]+)\]:\s*(\S+)`)
+	markdownListItemRegexp       = regexp.MustCompile(`^\s*[-*+]\s+(.*)$`)
+
+	headingUnreleasedRegexp = regexp.MustCompile(`(?i)^\[?unreleased\]?(\s|$)`)
+	headingVersionRegexp    = regexp.MustCompile(`(?:^|[^\w.])v?(\d+(?:\.\d+)+(?:-[0-9A-Za-z.-]*[0-9A-Za-z])?(?:\+[0-9A-Za-z.-]*[0-9A-Za-z])?)`)
+	headingDateRegexp       = regexp.MustCompile(`\d{4}-\d{2}-\d{2}|\d{4} [A-Z][a-z]{2} \d{2}`)
+)
+
+//=======================================
+// Models
+//=======================================
+
+// ChangelogReleaseSection is the section of a release in a Markdown changelog, the Version of the Unreleased section is empty.
+type ChangelogReleaseSection struct {
+	Version string
+	// Heading is the heading line of the section, e.g. ## [1.1.0] - 2016-02-03
+	Heading string
+	// Date is the date in the heading, as it is written
+	Date string
+	// Body is the content of the section after the heading
+	Body string
+	// Entries are the list items of the body
+	Entries []string
+}
+
+// ChangelogDocument is a Markdown changelog: the header, the release sections from the newest, and the footer.
+// The release sections are the headings (of the same level) starting with a version, or Unreleased.
+// The footer is the content after the last thematic break (e.g. releaseman's separator) of the last section,
+// or the link reference definitions at the end of the file.
+type ChangelogDocument struct {
+	Header   string
+	Releases []ChangelogReleaseSection
+	Footer   string
+}
+
+//=======================================
+// Utility
+//=======================================
+
+// releaseHeading returns the version of a release section heading's text, empty for the Unreleased section.
+func releaseHeading(text string) (string, bool) {
+	if headingUnreleasedRegexp.MatchString(text) {
+		return "", true
+	}
+	if match := headingVersionRegexp.FindStringSubmatch(text); match != nil {
+		return match[1], true
+	}
+	return "", false
+}
+
+func listEntries(body string) []string {
+	entries := []string{}
+	for _, line := range strings.Split(body, "\n") {
+		if match := markdownListItemRegexp.FindStringSubmatch(line); match != nil {
+			entries = append(entries, match[1])
+		}
+	}
+	return entries
+}
+
+// NewChangelogReleaseSection ...
+func NewChangelogReleaseSection(heading, body string) ChangelogReleaseSection {
+	section := ChangelogReleaseSection{
+		Heading: heading,
+		Date:    headingDateRegexp.FindString(heading),
+		Body:    strings.Trim(body, "\n"),
+		Entries: listEntries(body),
+	}
+	if match := markdownHeadingRegexp.FindStringSubmatch(heading); match != nil {
+		section.Version, _ = releaseHeading(match[2])
+	}
+	return section
+}
+
+// releaseHeadingLevel returns the heading level of the release sections: the level with the most release headings,
+// the deeper one on tie, as a title may contain a version too (e.g. ## Changelog (Current version: 1.1.0)).
+func releaseHeadingLevel(lines []string) int {
+	counts := map[int]int{}
+	inFence := false
+	for _, line := range lines {
+		if markdownFenceRegexp.MatchString(line) {
+			inFence = !inFence
+		}
+		if inFence {
+			continue
+		}
+		if match := markdownHeadingRegexp.FindStringSubmatch(line); match != nil {
+			if _, ok := releaseHeading(match[2]); ok {
+				counts[len(match[1])]++
+			}
+		}
+	}
+
+	level := 0
+	for l, count := range counts {
+		if count > counts[level] || (count == counts[level] && l > level) {
+			level = l
+		}
+	}
+	return level
+}
+
+// hasReleaseHeadingAfter returns true if any of the lines is a release section heading of the level.
+func hasReleaseHeadingAfter(lines []string, level int) bool {
+	for _, line := range lines {
+		if match := markdownHeadingRegexp.FindStringSubmatch(line); match != nil && len(match[1]) == level {
+			if _, ok := releaseHeading(match[2]); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+//=======================================
+// Main
+//=======================================
+
+// ParseChangelogDocument parses a Markdown changelog, it does not have to be created by releaseman.
+func ParseChangelogDocument(changelog string) ChangelogDocument {
+	lines := strings.Split(strings.TrimRight(changelog, "\n"), "\n")
+	level := releaseHeadingLevel(lines)
+
+	// the footer starts at the link reference definitions at the end of the file
+	footerIdx := len(lines)
+	for idx := len(lines) - 1; idx >= 0; idx-- {
+		if line := strings.TrimSpace(lines[idx]); line != "" && !markdownLinkDefinitionRegexp.MatchString(line) {
+			break
+		}
+		footerIdx = idx
+	}
+
+	document := ChangelogDocument{}
+	header := []string{}
+	heading := ""
+	body := []string{}
+	closeSection := func() {
+		if heading != "" {
+			document.Releases = append(document.Releases, NewChangelogReleaseSection(heading, strings.Join(body, "\n")))
+		}
+	}
+
+	inFence := false
+	for idx, line := range lines[:footerIdx] {
+		if markdownFenceRegexp.MatchString(line) {
+			inFence = !inFence
+		}
+
+		if !inFence {
+			if match := markdownHeadingRegexp.FindStringSubmatch(line); match != nil && len(match[1]) == level {
+				if _, ok := releaseHeading(match[2]); ok {
+					closeSection()
+					heading, body = line, []string{}
+					continue
+				}
+			}
+		}
+
+		if heading == "" {
+			header = append(header, line)
+		} else if !inFence && markdownThematicBreakRegexp.MatchString(line) && !hasReleaseHeadingAfter(lines[idx+1:footerIdx], level) {
+			// the thematic break after the last release section starts the footer
+			footerIdx = idx
+			break
+		} else {
+			body = append(body, line)
+		}
+	}
+	closeSection()
+
+	document.Header = strings.TrimSpace(strings.Join(header, "\n"))
+	document.Footer = strings.TrimSpace(strings.Join(lines[footerIdx:], "\n"))
+	return document
+}
+
+// Release returns the index of the section of the version (empty version means the Unreleased section), -1 if it does not exist.
+func (document ChangelogDocument) Release(version string) int {
+	for idx, release := range document.Releases {
+		if release.Version == version {
+			return idx
+		}
+	}
+	return -1
+}
+
+// Insert adds the section of a new version before the first section of a lower version,
+// the section is the newest one (after the Unreleased section) if the listed versions can not be compared.
+func (document *ChangelogDocument) Insert(section ChangelogReleaseSection, scheme versioning.Scheme) error {
+	if document.Release(section.Version) != -1 {
+		return fmt.Errorf("The changelog already contains the section of version (%s)", section.Version)
+	}
+
+	idx := 0
+	if section.Version != "" {
+		if len(document.Releases) > 0 && document.Releases[0].Version == "" {
+			idx = 1
+		}
+
+		compared := false
+		for otherIdx, other := range document.Releases {
+			if other.Version == "" {
+				continue
+			}
+			compare, err := scheme.Compare(section.Version, other.Version)
+			if err != nil {
+				continue
+			}
+			if compare > 0 {
+				idx = otherIdx
+				compared = false
+				break
+			}
+			compared = true
+		}
+		if compared {
+			// every listed version is higher
+			idx = len(document.Releases)
+		}
+	}
+
+	document.Releases = append(document.Releases[:idx], append([]ChangelogReleaseSection{section}, document.Releases[idx:]...)...)
+	return nil
+}
+
+// Replace replaces the section of the same version.
+func (document *ChangelogDocument) Replace(section ChangelogReleaseSection) error {
+	idx := document.Release(section.Version)
+	if idx == -1 {
+		return fmt.Errorf("The changelog does not contain the section of version (%s)", section.Version)
+	}
+	document.Releases[idx] = section
+	return nil
+}
+
+// Upsert replaces the section of the same version, or inserts the section of the new version.
+func (document *ChangelogDocument) Upsert(section ChangelogReleaseSection, scheme versioning.Scheme) error {
+	if document.Release(section.Version) != -1 {
+		return document.Replace(section)
+	}
+	return document.Insert(section, scheme)
+}
+
+// Remove removes the section of the version.
+func (document *ChangelogDocument) Remove(version string) error {
+	idx := document.Release(version)
+	if idx == -1 {
+		return fmt.Errorf("The changelog does not contain the section of version (%s)", version)
+	}
+	document.Releases = append(document.Releases[:idx], document.Releases[idx+1:]...)
+	return nil
+}
+
+// String renders the changelog, the header, the sections and the footer are separated by empty lines.
+func (document ChangelogDocument) String() string {
+	parts := []string{}
+	if document.Header != "" {
+		parts = append(parts, document.Header)
+	}
+	for _, release := range document.Releases {
+		if release.Body == "" {
+			parts = append(parts, release.Heading)
+		} else {
+			parts = append(parts, release.Heading+"\n\n"+release.Body)
+		}
+	}
+	if document.Footer != "" {
+		parts = append(parts, document.Footer)
+	}
+	return strings.Join(parts, "\n\n") + "\n"
+}
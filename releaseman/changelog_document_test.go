@@ -0,0 +1,128 @@
+package releaseman
+
+import (
+	"testing"
+
+	"github.com/bitrise-tools/releaseman/versioning"
+	"github.com/stretchr/testify/require"
+)
+
+const testReleasemanChangelog = `## Changelog (Current version: 1.0.1)
+
+-----------------
+
+### 1.0.1 - 1.0.0 (2016 Feb 03)
+
+* [4e4ac44] bitrise - fix crash (2016 Feb 03)
+
+### 1.0.0 -  (2016 Feb 01)
+
+* [ad85ba2] bitrise - first release (2016 Feb 01)
+
+-----------------
+
+Updated: 2016 Feb 03
+`
+
+const testForeignChangelog = "# History\n\n" +
+	"Notable changes of the project.\n\n" +
+	"## v2.0.0 (2020-01-01)\n\n" +
+	"- dropped the legacy API\n" +
+	"  - see the migration guide\n\n" +
+	"```\n## 1.5.0\n```\n\n" +
+	"## v1.0.0 (2019-06-01)\n\n" +
+	"Initial release.\n"
+
+func TestParseChangelogDocument(t *testing.T) {
+	t.Log("releaseman layout")
+	{
+		document := ParseChangelogDocument(testReleasemanChangelog)
+		require.Equal(t, "## Changelog (Current version: 1.0.1)\n\n-----------------", document.Header)
+		require.Equal(t, "-----------------\n\nUpdated: 2016 Feb 03", document.Footer)
+		require.Equal(t, 2, len(document.Releases))
+		require.Equal(t, ChangelogReleaseSection{
+			Version: "1.0.1",
+			Heading: "### 1.0.1 - 1.0.0 (2016 Feb 03)",
+			Date:    "2016 Feb 03",
+			Body:    "* [4e4ac44] bitrise - fix crash (2016 Feb 03)",
+			Entries: []string{"[4e4ac44] bitrise - fix crash (2016 Feb 03)"},
+		}, document.Releases[0])
+		require.Equal(t, "1.0.0", document.Releases[1].Version)
+		require.Equal(t, testReleasemanChangelog, document.String())
+	}
+
+	t.Log("keepachangelog layout")
+	{
+		document := ParseChangelogDocument(testKeepAChangelog)
+		require.Equal(t, "# Changelog\n\nAll notable changes to this project will be documented in this file.", document.Header)
+		require.Equal(t, []string{"", "1.0.0"}, []string{document.Releases[0].Version, document.Releases[1].Version})
+		require.Equal(t, "2026-01-02", document.Releases[1].Date)
+		require.Equal(t, []string{"crash on start"}, document.Releases[1].Entries)
+		require.Contains(t, document.Footer, "[1.0.0]: https://github.com/bitrise-tools/releaseman/releases/tag/v1.0.0")
+		require.Equal(t, testKeepAChangelog, document.String())
+	}
+
+	t.Log("not created by releaseman")
+	{
+		document := ParseChangelogDocument(testForeignChangelog)
+		require.Equal(t, "# History\n\nNotable changes of the project.", document.Header)
+		require.Equal(t, "", document.Footer)
+		require.Equal(t, 2, len(document.Releases))
+		require.Equal(t, "2.0.0", document.Releases[0].Version)
+		require.Equal(t, []string{"dropped the legacy API", "see the migration guide"}, document.Releases[0].Entries)
+		require.Contains(t, document.Releases[0].Body, "```\n## 1.5.0\n```")
+		require.Equal(t, "1.0.0", document.Releases[1].Version)
+		require.Equal(t, testForeignChangelog, document.String())
+	}
+
+	t.Log("no releases")
+	{
+		document := ParseChangelogDocument("# Changelog\n")
+		require.Equal(t, "# Changelog", document.Header)
+		require.Equal(t, 0, len(document.Releases))
+	}
+}
+
+func TestChangelogDocumentEditing(t *testing.T) {
+	scheme := versioning.SemVer{}
+	document := ParseChangelogDocument(testForeignChangelog)
+
+	t.Log("insert")
+	{
+		require.NoError(t, document.Insert(NewChangelogReleaseSection("## v2.1.0 (2020-02-01)", "- dark mode"), scheme))
+		require.NoError(t, document.Insert(NewChangelogReleaseSection("## v1.5.0 (2019-12-01)", "- offline sync"), scheme))
+		require.NoError(t, document.Insert(NewChangelogReleaseSection("## v0.9.0 (2019-01-01)", "- beta"), scheme))
+		require.NoError(t, document.Insert(NewChangelogReleaseSection("## Unreleased", "- wip"), scheme))
+		require.Error(t, document.Insert(NewChangelogReleaseSection("## v2.1.0", ""), scheme))
+
+		versions := []string{}
+		for _, release := range document.Releases {
+			versions = append(versions, release.Version)
+		}
+		require.Equal(t, []string{"", "2.1.0", "2.0.0", "1.5.0", "1.0.0", "0.9.0"}, versions)
+
+		require.NoError(t, document.Insert(NewChangelogReleaseSection("## v3.0.0", ""), scheme))
+		require.Equal(t, "3.0.0", document.Releases[1].Version)
+	}
+
+	t.Log("replace")
+	{
+		require.NoError(t, document.Replace(NewChangelogReleaseSection("## v1.5.0 (2019-12-02)", "- offline sync\n- fixes")))
+		idx := document.Release("1.5.0")
+		require.Equal(t, "2019-12-02", document.Releases[idx].Date)
+		require.Equal(t, []string{"offline sync", "fixes"}, document.Releases[idx].Entries)
+
+		require.Error(t, document.Replace(NewChangelogReleaseSection("## v1.6.0", "")))
+
+		require.NoError(t, document.Upsert(NewChangelogReleaseSection("## v1.5.0", ""), scheme))
+		require.Equal(t, "", document.Releases[idx].Body)
+	}
+
+	t.Log("remove")
+	{
+		require.NoError(t, document.Remove("1.5.0"))
+		require.Equal(t, -1, document.Release("1.5.0"))
+		require.Error(t, document.Remove("1.5.0"))
+		require.Contains(t, document.String(), "## v2.0.0 (2020-01-01)\n\n- dropped the legacy API")
+	}
+}
@@ -9,50 +9,105 @@ import (
 )
 
 func TestCommitsBetween(t *testing.T) {
-	unixTimestampStart := time.Unix(1454498673, 0)
-	unixTimestampEnd := time.Unix(1454498683, 0)
-	unixTimestampBeforeEnd := time.Unix(1454498682, 0)
-
+	// 1 <- 2 (tag: 1.0.0) <- 4 <- 5 (tag: 1.1.0) <- 6
+	//  \                   /
+	//   3 ----------------- (feature branch, merged by 4)
+	//
+	// commit 3 is dated after 1.1.0 (clock skew), but it is part of 1.1.0 by ancestry
 	allCommits := []git.CommitModel{
 		git.CommitModel{
-			Date: time.Unix(1454498663, 0),
-			Hash: "1",
+			Hash:    "6",
+			Date:    time.Unix(1454498693, 0),
+			Parents: []string{"5"},
+		},
+		git.CommitModel{
+			Hash:    "5",
+			Date:    time.Unix(1454498683, 0),
+			Parents: []string{"4"},
+		},
+		git.CommitModel{
+			Hash:    "4",
+			Date:    time.Unix(1454498682, 0),
+			Parents: []string{"2", "3"},
 		},
 		git.CommitModel{
-			Date: unixTimestampStart,
-			Hash: "2",
+			Hash:    "3",
+			Date:    time.Unix(1454498703, 0),
+			Parents: []string{"1"},
 		},
 		git.CommitModel{
-			Date: unixTimestampBeforeEnd,
-			Hash: "3",
+			Hash:    "2",
+			Date:    time.Unix(1454498673, 0),
+			Parents: []string{"1"},
 		},
 		git.CommitModel{
-			Date: time.Unix(1454498693, 0),
-			Hash: "4",
+			Hash:    "1",
+			Date:    time.Unix(1454498663, 0),
+			Parents: []string{},
 		},
 	}
 
+	startCommit := git.CommitModel{Hash: "2", Tag: "1.0.0"}
+	endCommit := git.CommitModel{Hash: "5", Tag: "1.1.0"}
+
 	commits := commitsBetween(nil, nil, allCommits)
+	require.Equal(t, 6, len(commits))
+	require.Equal(t, "6", commits[0].Hash)
+	require.Equal(t, "1", commits[5].Hash)
+
+	commits = commitsBetween(nil, &endCommit, allCommits)
+	require.Equal(t, 5, len(commits))
+	require.Equal(t, "5", commits[0].Hash)
+	require.Equal(t, "4", commits[1].Hash)
+	require.Equal(t, "3", commits[2].Hash)
+	require.Equal(t, "2", commits[3].Hash)
+	require.Equal(t, "1", commits[4].Hash)
+
+	commits = commitsBetween(&startCommit, nil, allCommits)
 	require.Equal(t, 4, len(commits))
-	require.Equal(t, "4", commits[0].Hash)
-	require.Equal(t, "3", commits[1].Hash)
-	require.Equal(t, "2", commits[2].Hash)
-	require.Equal(t, "1", commits[3].Hash)
+	require.Equal(t, "6", commits[0].Hash)
+	require.Equal(t, "5", commits[1].Hash)
+	require.Equal(t, "4", commits[2].Hash)
+	require.Equal(t, "3", commits[3].Hash)
 
-	commits = commitsBetween(nil, &unixTimestampEnd, allCommits)
+	commits = commitsBetween(&startCommit, &endCommit, allCommits)
 	require.Equal(t, 3, len(commits))
-	require.Equal(t, "3", commits[0].Hash)
-	require.Equal(t, "2", commits[1].Hash)
-	require.Equal(t, "1", commits[2].Hash)
-
-	commits = commitsBetween(&unixTimestampStart, nil, allCommits)
-	require.Equal(t, 2, len(commits))
-	require.Equal(t, "4", commits[0].Hash)
-	require.Equal(t, "3", commits[1].Hash)
-
-	commits = commitsBetween(&unixTimestampStart, &unixTimestampEnd, allCommits)
-	require.Equal(t, 1, len(commits))
-	require.Equal(t, "3", commits[0].Hash)
+	require.Equal(t, "5", commits[0].Hash)
+	require.Equal(t, "4", commits[1].Hash)
+	require.Equal(t, "3", commits[2].Hash)
+
+	t.Log("start commit outside of the list (already excluded by the git revision range)")
+	{
+		commits = commitsBetween(&git.CommitModel{Hash: "0"}, &endCommit, allCommits[:4])
+		require.Equal(t, 3, len(commits))
+		require.Equal(t, "5", commits[0].Hash)
+		require.Equal(t, "4", commits[1].Hash)
+		require.Equal(t, "3", commits[2].Hash)
+	}
+
+	t.Log("tags on the merge commits of the release branch")
+	{
+		// develop: d1 <- d2 <- d3 <- d4 <- d5
+		// master:  m1 (tag: 1.0.0, merges d2) <- m2 (tag: 1.1.0, merges d4)
+		developCommits := []git.CommitModel{
+			git.CommitModel{Hash: "d5", Parents: []string{"d4"}},
+			git.CommitModel{Hash: "d4", Parents: []string{"d3"}},
+			git.CommitModel{Hash: "d3", Parents: []string{"d2"}},
+			git.CommitModel{Hash: "d2", Parents: []string{"d1"}},
+			git.CommitModel{Hash: "d1", Parents: []string{}},
+		}
+		firstRelease := git.CommitModel{Hash: "m1", Tag: "1.0.0", Parents: []string{"d2"}}
+		secondRelease := git.CommitModel{Hash: "m2", Tag: "1.1.0", Parents: []string{"m1", "d4"}}
+
+		commits = commitsBetween(&firstRelease, &secondRelease, developCommits)
+		require.Equal(t, 2, len(commits))
+		require.Equal(t, "d4", commits[0].Hash)
+		require.Equal(t, "d3", commits[1].Hash)
+
+		commits = commitsBetween(&secondRelease, nil, developCommits)
+		require.Equal(t, 1, len(commits))
+		require.Equal(t, "d5", commits[0].Hash)
+	}
 }
 
 func TestReversedSections(t *testing.T) {
@@ -84,3 +139,114 @@ func TestReversedSections(t *testing.T) {
 	require.Equal(t, "2", reversed[1].StartTaggedCommit.Tag)
 	require.Equal(t, "1", reversed[2].StartTaggedCommit.Tag)
 }
+
+func TestGroupCommits(t *testing.T) {
+	commits := []git.CommitModel{
+		git.CommitModel{Hash: "1", Type: "feat"},
+		git.CommitModel{Hash: "2", Type: "fix"},
+		git.CommitModel{Hash: "3", Type: "feat", Breaking: true},
+		git.CommitModel{Hash: "4", Type: "docs"},
+		git.CommitModel{Hash: "5"},
+	}
+
+	sections := groupCommits(commits, DefaultChangelogSections)
+	require.Equal(t, 3, len(sections))
+	require.Equal(t, "Breaking Changes", sections[0].Title)
+	require.Equal(t, 1, len(sections[0].Commits))
+	require.Equal(t, "3", sections[0].Commits[0].Hash)
+	require.Equal(t, "Features", sections[1].Title)
+	require.Equal(t, 1, len(sections[1].Commits))
+	require.Equal(t, "1", sections[1].Commits[0].Hash)
+	require.Equal(t, "Bug Fixes", sections[2].Title)
+	require.Equal(t, 1, len(sections[2].Commits))
+	require.Equal(t, "2", sections[2].Commits[0].Hash)
+
+	sections = groupCommits(commits, []ChangelogSection{
+		ChangelogSection{Title: "Fixes", Types: []string{"fix"}},
+		ChangelogSection{Title: "Documentation", Types: []string{"docs"}},
+		ChangelogSection{Title: "Other"},
+	})
+	require.Equal(t, 3, len(sections))
+	require.Equal(t, "Fixes", sections[0].Title)
+	require.Equal(t, "Documentation", sections[1].Title)
+	require.Equal(t, "Other", sections[2].Title)
+	require.Equal(t, 3, len(sections[2].Commits))
+	require.Equal(t, "1", sections[2].Commits[0].Hash)
+	require.Equal(t, "3", sections[2].Commits[1].Hash)
+	require.Equal(t, "5", sections[2].Commits[2].Hash)
+
+	sections = groupCommits([]git.CommitModel{}, DefaultChangelogSections)
+	require.Equal(t, 0, len(sections))
+}
+
+func TestGenerateChangelogHistory(t *testing.T) {
+	// 1 <- 2 (tag: 1.0.0) <- 3 <- 4 (tag: 1.1.0) <- 5
+	taggedCommits := []git.CommitModel{
+		git.CommitModel{Hash: "2", Parents: []string{"1"}, Tag: "1.0.0", Version: "1.0.0", Type: "feat", Description: "first release"},
+		git.CommitModel{Hash: "4", Parents: []string{"3"}, Tag: "1.1.0", Version: "1.1.0", Type: "fix", Description: "crash"},
+	}
+	commits := []git.CommitModel{
+		git.CommitModel{Hash: "5", Parents: []string{"4"}, Type: "feat", Description: "unreleased"},
+		taggedCommits[1],
+		git.CommitModel{Hash: "3", Parents: []string{"2"}, Type: "feat", Description: "dark mode"},
+		taggedCommits[0],
+		git.CommitModel{Hash: "1", Description: "initial commit"},
+	}
+
+	t.Log("every tagged commit is a release")
+	{
+		changelog := generateChangelogHistory(commits, taggedCommits, Config{})
+		require.Equal(t, "1.1.0", changelog.Version)
+		require.Equal(t, 2, len(changelog.ContentItems))
+
+		require.Equal(t, "1.1.0", changelog.ContentItems[0].EndTaggedCommit.Tag)
+		require.Equal(t, "1.0.0", changelog.ContentItems[0].StartTaggedCommit.Tag)
+		require.Equal(t, []string{"4", "3"}, commitHashes(changelog.ContentItems[0].Commits))
+
+		require.Equal(t, "1.0.0", changelog.ContentItems[1].EndTaggedCommit.Tag)
+		require.Equal(t, "", changelog.ContentItems[1].StartTaggedCommit.Tag)
+		require.Equal(t, []string{"2", "1"}, commitHashes(changelog.ContentItems[1].Commits))
+	}
+
+	t.Log("keepachangelog lists the unreleased commits")
+	{
+		changelog := generateChangelogHistory(commits, taggedCommits, Config{Changelog: Changelog{Format: KeepAChangelogFormat}})
+		require.Equal(t, 3, len(changelog.ContentItems))
+		require.Equal(t, "", changelog.ContentItems[0].EndTaggedCommit.Version)
+		require.Equal(t, []string{"5"}, commitHashes(changelog.ContentItems[0].Commits))
+		require.Equal(t, "Added", changelog.ContentItems[0].Sections[0].Title)
+	}
+
+	t.Log("tags on the merge commits of the release branch")
+	{
+		// develop: 1 <- 2 <- 3 <- 4 <- 5
+		// master:  m1 (tag: 1.0.0, merges 2) <- m2 (tag: 1.1.0, merges 4)
+		releaseTaggedCommits := []git.CommitModel{
+			git.CommitModel{Hash: "m1", Parents: []string{"2"}, Tag: "1.0.0", Version: "1.0.0"},
+			git.CommitModel{Hash: "m2", Parents: []string{"m1", "4"}, Tag: "1.1.0", Version: "1.1.0"},
+		}
+		developCommits := []git.CommitModel{
+			git.CommitModel{Hash: "5", Parents: []string{"4"}},
+			git.CommitModel{Hash: "4", Parents: []string{"3"}},
+			git.CommitModel{Hash: "3", Parents: []string{"2"}},
+			git.CommitModel{Hash: "2", Parents: []string{"1"}},
+			git.CommitModel{Hash: "1"},
+		}
+
+		changelog := generateChangelogHistory(developCommits, releaseTaggedCommits, Config{})
+		require.Equal(t, "1.1.0", changelog.Version)
+		require.Equal(t, 2, len(changelog.ContentItems))
+		require.Equal(t, "1.1.0", changelog.ContentItems[0].EndTaggedCommit.Tag)
+		require.Equal(t, []string{"4", "3"}, commitHashes(changelog.ContentItems[0].Commits))
+		require.Equal(t, "1.0.0", changelog.ContentItems[1].EndTaggedCommit.Tag)
+		require.Equal(t, []string{"2", "1"}, commitHashes(changelog.ContentItems[1].Commits))
+	}
+}
+
+func commitHashes(commits []git.CommitModel) []string {
+	hashes := []string{}
+	for _, commit := range commits {
+		hashes = append(hashes, commit.Hash)
+	}
+	return hashes
+}
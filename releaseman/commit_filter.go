@@ -0,0 +1,79 @@
+package releaseman
+
+import (
+	"path"
+	"strings"
+
+	"github.com/bitrise-tools/releaseman/git"
+)
+
+//=======================================
+// Utility
+//=======================================
+
+// matchesPath returns true if the file is the pattern, is inside the pattern directory
+// or matches the pattern as a glob.
+func matchesPath(file, pattern string) bool {
+	pattern = strings.TrimPrefix(pattern, "./")
+	if pattern == "" || pattern == "." {
+		return true
+	}
+
+	dir := strings.TrimSuffix(pattern, "/")
+	if file == dir || strings.HasPrefix(file, dir+"/") {
+		return true
+	}
+
+	matched, err := path.Match(pattern, file)
+	return err == nil && matched
+}
+
+func matchesAnyPath(file string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matchesPath(file, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+//=======================================
+// Main
+//=======================================
+
+// isFilteringCommits returns true if the commits are filtered by the files they changed.
+func (config Config) isFilteringCommits() bool {
+	return config.Component != nil || len(config.Changelog.IncludePaths) > 0 || len(config.Changelog.ExcludePaths) > 0
+}
+
+// matchesFile returns true if the file belongs to the released component,
+// is included by the include paths (if any) and is not excluded by the exclude paths.
+func (config Config) matchesFile(file string) bool {
+	if config.Component != nil && !matchesAnyPath(file, config.Component.Paths) {
+		return false
+	}
+	if len(config.Changelog.IncludePaths) > 0 && !matchesAnyPath(file, config.Changelog.IncludePaths) {
+		return false
+	}
+	return !matchesAnyPath(file, config.Changelog.ExcludePaths)
+}
+
+// FilterCommits returns the commits which changed at least one matching file:
+// a file of the released component, matching the include paths and not matching the exclude paths.
+// Every commit is returned if no filter is configured, otherwise the merge commits (without changed files) are dropped.
+func (config Config) FilterCommits(commits []git.CommitModel) []git.CommitModel {
+	if !config.isFilteringCommits() {
+		return commits
+	}
+
+	filteredCommits := []git.CommitModel{}
+	for _, commit := range commits {
+		for _, file := range commit.Files {
+			if config.matchesFile(file) {
+				filteredCommits = append(filteredCommits, commit)
+				break
+			}
+		}
+	}
+	return filteredCommits
+}
@@ -0,0 +1,65 @@
+package releaseman
+
+import (
+	"testing"
+
+	"github.com/bitrise-tools/releaseman/git"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterCommits(t *testing.T) {
+	commits := []git.CommitModel{
+		{Hash: "1", Files: []string{"services/api/main.go"}},
+		{Hash: "2", Files: []string{"services/apigateway/main.go"}},
+		{Hash: "3", Files: []string{"web/index.js", "README.md"}},
+		{Hash: "4", Files: []string{"web/lib/util.js"}},
+		{Hash: "5", Files: []string{"go.mod"}},
+		{Hash: "6"},
+	}
+
+	hashes := func(commits []git.CommitModel) []string {
+		hashes := []string{}
+		for _, commit := range commits {
+			hashes = append(hashes, commit.Hash)
+		}
+		return hashes
+	}
+
+	t.Log("no component")
+	{
+		require.Equal(t, 6, len(Config{}.FilterCommits(commits)))
+	}
+
+	t.Log("directory and file paths")
+	{
+		config := Config{Component: &Component{Name: "api", Paths: []string{"services/api/", "go.mod"}}}
+		require.Equal(t, []string{"1", "5"}, hashes(config.FilterCommits(commits)))
+	}
+
+	t.Log("glob paths")
+	{
+		config := Config{Component: &Component{Name: "web", Paths: []string{"web/*.js"}}}
+		require.Equal(t, []string{"3"}, hashes(config.FilterCommits(commits)))
+	}
+
+	t.Log("include and exclude paths")
+	{
+		config := Config{Changelog: Changelog{IncludePaths: []string{"web"}}}
+		require.Equal(t, []string{"3", "4"}, hashes(config.FilterCommits(commits)))
+
+		config = Config{Changelog: Changelog{ExcludePaths: []string{"*.md", "web/lib"}}}
+		require.Equal(t, []string{"1", "2", "3", "5"}, hashes(config.FilterCommits(commits)))
+
+		config = Config{Changelog: Changelog{IncludePaths: []string{"web"}, ExcludePaths: []string{"web/index.js"}}}
+		require.Equal(t, []string{"4"}, hashes(config.FilterCommits(commits)))
+	}
+
+	t.Log("component and exclude paths")
+	{
+		config := Config{
+			Component: &Component{Name: "web", Paths: []string{"web"}},
+			Changelog: Changelog{ExcludePaths: []string{"web/lib"}},
+		}
+		require.Equal(t, []string{"3"}, hashes(config.FilterCommits(commits)))
+	}
+}
@@ -0,0 +1,75 @@
+package releaseman
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bitrise-tools/releaseman/git"
+)
+
+//=======================================
+// Models
+//=======================================
+
+// Component is an independently versioned part of a monorepo.
+type Component struct {
+	Name string `yaml:"name"`
+	// Paths of the component, only the commits touching these paths belong to the component
+	Paths []string `yaml:"paths"`
+	// TagFormat is the tag namespace of the component, defaults to <name>/{{.Version}}
+	TagFormat git.TagFormat `yaml:"tag_format,omitempty"`
+	// ChangelogPath is required: the changelog sections are keyed by version,
+	// so the releases of two components would overwrite each other in a shared changelog
+	ChangelogPath    string        `yaml:"changelog_path,omitempty"`
+	GetVersionScript string        `yaml:"get_version_script,omitempty"`
+	SetVersionScript string        `yaml:"set_version_script,omitempty"`
+	VersionFiles     []VersionFile `yaml:"version_files,omitempty"`
+}
+
+//=======================================
+// Main
+//=======================================
+
+// Validate ...
+func (component Component) Validate() error {
+	if component.ChangelogPath == "" {
+		return fmt.Errorf("Invalid component (%s): missing changelog_path, the components can not share a changelog", component.Name)
+	}
+	return nil
+}
+
+// ComponentNames ...
+func (config Config) ComponentNames() []string {
+	names := []string{}
+	for _, component := range config.Components {
+		names = append(names, component.Name)
+	}
+	return names
+}
+
+// WithComponent returns the config of releasing the given component:
+// the component's tag format, changelog path and version files are used and its commits are selected by its paths.
+func (config Config) WithComponent(name string) (Config, error) {
+	for _, component := range config.Components {
+		if component.Name != name {
+			continue
+		}
+
+		if component.TagFormat == "" {
+			component.TagFormat = git.TagFormat(component.Name + "/" + string(git.DefaultTagFormat))
+		}
+		if err := component.TagFormat.Validate(); err != nil {
+			return Config{}, err
+		}
+
+		config.Component = &component
+		config.Release.TagFormat = component.TagFormat
+		config.Changelog.Path = component.ChangelogPath
+		// the version files of the repository are not the component's, even if the component has none
+		config.Release.VersionFiles = component.VersionFiles
+
+		return config, nil
+	}
+
+	return Config{}, fmt.Errorf("Component (%s) not found, available components: %s", name, strings.Join(config.ComponentNames(), ", "))
+}
@@ -0,0 +1,77 @@
+package releaseman
+
+import (
+	"testing"
+
+	"github.com/bitrise-tools/releaseman/git"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComponents(t *testing.T) {
+	configStr := `
+release:
+  development_branch: master
+  release_branch: master
+  version_files:
+  - path: VERSION
+changelog:
+  path: CHANGELOG.md
+components:
+- name: api
+  paths:
+  - services/api
+  - go.mod
+  changelog_path: services/api/CHANGELOG.md
+  get_version_script: cat services/api/VERSION
+- name: web
+  paths:
+  - "web/*.js"
+  tag_format: web-v{{.Version}}
+  changelog_path: web/CHANGELOG.md
+  version_files:
+  - path: web/package.json
+`
+	config, err := NewConfigFromBytes([]byte(configStr))
+	require.NoError(t, err)
+	require.Equal(t, []string{"api", "web"}, config.ComponentNames())
+	require.Nil(t, config.Component)
+
+	t.Log("default tag format")
+	{
+		apiConfig, err := config.WithComponent("api")
+		require.NoError(t, err)
+		require.Equal(t, "api", apiConfig.Component.Name)
+		require.Equal(t, "cat services/api/VERSION", apiConfig.Component.GetVersionScript)
+		require.Equal(t, git.TagFormat("api/{{.Version}}"), apiConfig.Release.TagFormat)
+		require.Equal(t, "services/api/CHANGELOG.md", apiConfig.Changelog.Path)
+		// the version files of the repository do not belong to the component
+		require.Equal(t, 0, len(apiConfig.Release.VersionFiles))
+
+		apiConfig.Release.Version = "1.1.0"
+		require.Equal(t, "api/1.1.0", apiConfig.ReleaseTag())
+	}
+
+	t.Log("custom tag format and changelog path")
+	{
+		webConfig, err := config.WithComponent("web")
+		require.NoError(t, err)
+		require.Equal(t, git.TagFormat("web-v{{.Version}}"), webConfig.Release.TagFormat)
+		require.Equal(t, "web/CHANGELOG.md", webConfig.Changelog.Path)
+		require.Equal(t, []VersionFile{{Path: "web/package.json"}}, webConfig.Release.VersionFiles)
+	}
+
+	t.Log("unknown component")
+	{
+		_, err := config.WithComponent("ios")
+		require.Error(t, err)
+	}
+
+	t.Log("the components can not share a changelog")
+	{
+		_, err := NewConfigFromBytes([]byte("components:\n- name: api\n  paths: [api]\n"))
+		require.Error(t, err)
+
+		_, err = NewConfigFromBytes([]byte("components:\n- name: api\n  changelog_path: CHANGELOG.md\n- name: web\n  changelog_path: CHANGELOG.md\n"))
+		require.Error(t, err)
+	}
+}
@@ -2,9 +2,12 @@ package releaseman
 
 import (
 	"fmt"
+	"strings"
 
 	log "github.com/Sirupsen/logrus"
 	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-tools/releaseman/git"
+	"github.com/bitrise-tools/releaseman/versioning"
 	"gopkg.in/yaml.v2"
 )
 
@@ -26,25 +29,87 @@ var (
 // Models
 //=======================================
 
+// Tag ...
+type Tag struct {
+	// Annotated tags store the tagger, the date and a message
+	Annotated bool `yaml:"annotated,omitempty"`
+	// MessageTemplate is rendered with the release notes model, defaults to the release notes
+	MessageTemplate string `yaml:"message_template,omitempty"`
+	// Sign makes a signed (and annotated) tag
+	Sign bool `yaml:"sign,omitempty"`
+}
+
+// Signing ...
+type Signing struct {
+	// Format is gpg (default) or ssh
+	Format string `yaml:"format,omitempty"`
+	// Key is the gpg key id or the path of the ssh key
+	Key string `yaml:"key,omitempty"`
+}
+
 // Release ...
 type Release struct {
 	DevelopmentBranch string `yaml:"development_branch"`
 	ReleaseBranch     string `yaml:"release_branch"`
 	Version           string `yaml:"version,omitempty"`
+	// VersionScheme is semver (default) or calver, formatted by CalVerFormat (e.g. YYYY.MM.MICRO)
+	VersionScheme string `yaml:"version_scheme,omitempty"`
+	CalVerFormat  string `yaml:"calver_format,omitempty"`
+	// VersionFiles store the version of the project, read as the current version and updated on release
+	VersionFiles []VersionFile `yaml:"version_files,omitempty"`
+	// PrereleaseIdentifier of the pre-release version bumps, e.g. rc (default), beta
+	PrereleaseIdentifier string `yaml:"prerelease_identifier,omitempty"`
+	// BuildMetadata template appended to the version, e.g. {{.ShortCommit}}
+	BuildMetadata string `yaml:"build_metadata,omitempty"`
+	// TagFormat is the template of the release tags, e.g. v{{.Version}}
+	TagFormat git.TagFormat `yaml:"tag_format,omitempty"`
+	// Push the release branch, the development branch and the tag to the remote
+	Push        bool    `yaml:"push,omitempty"`
+	Remote      string  `yaml:"remote,omitempty"`
+	Tag         Tag     `yaml:"tag,omitempty"`
+	SignCommits bool    `yaml:"sign_commits,omitempty"`
+	Signing     Signing `yaml:"signing,omitempty"`
+}
+
+// ChangelogSection ...
+type ChangelogSection struct {
+	Title string `yaml:"title"`
+	// Conventional Commits types listed in the section, empty list means any type
+	Types []string `yaml:"types,omitempty"`
+	// Breaking sections list the breaking changes only
+	Breaking bool `yaml:"breaking,omitempty"`
 }
 
 // Changelog ...
 type Changelog struct {
-	Path            string `yaml:"path"`
-	ContentTemplate string `yaml:"content_template"`
-	HeaderTemplate  string `yaml:"header_template"`
-	FooterTemplate  string `yaml:"footer_template"`
+	Path string `yaml:"path"`
+	// Format is empty (the changelog is rendered by the templates) or keepachangelog
+	Format string `yaml:"format,omitempty"`
+	// RepositoryURL is the base of the compare links of the keepachangelog format, e.g. https://github.com/bitrise-tools/releaseman
+	RepositoryURL   string             `yaml:"repository_url,omitempty"`
+	ContentTemplate string             `yaml:"content_template"`
+	HeaderTemplate  string             `yaml:"header_template"`
+	FooterTemplate  string             `yaml:"footer_template"`
+	Sections        []ChangelogSection `yaml:"sections,omitempty"`
+	// IncludePaths and ExcludePaths filter the changelog's commits by the files they changed
+	IncludePaths []string `yaml:"include_paths,omitempty"`
+	ExcludePaths []string `yaml:"exclude_paths,omitempty"`
+	// DataPath is the path of the machine-readable changelog (see ChangelogData), written next to the changelog
+	DataPath string `yaml:"data_path,omitempty"`
+	// DataFormat is json or yaml, detected from the DataPath extension if empty
+	DataFormat string `yaml:"data_format,omitempty"`
+	// StoreNotes are the per locale app store release notes, written next to the changelog
+	StoreNotes []StoreNotes `yaml:"store_notes,omitempty"`
 }
 
 // Config ...
 type Config struct {
-	Release   Release   `yaml:"release,omitempty"`
-	Changelog Changelog `yaml:"changelog,omitempty"`
+	Release    Release     `yaml:"release,omitempty"`
+	Changelog  Changelog   `yaml:"changelog,omitempty"`
+	Components []Component `yaml:"components,omitempty"`
+
+	// Component is the released component, see WithComponent
+	Component *Component `yaml:"-"`
 }
 
 // NewConfigFromFile ...
@@ -59,8 +124,9 @@ func NewConfigFromFile(pth string) (Config, error) {
 // NewConfigFromBytes ...
 func NewConfigFromBytes(bytes []byte) (Config, error) {
 	type FileConfig struct {
-		Release   *Release   `yaml:"release,omitempty"`
-		Changelog *Changelog `yaml:"changelog,omitempty"`
+		Release    *Release    `yaml:"release,omitempty"`
+		Changelog  *Changelog  `yaml:"changelog,omitempty"`
+		Components []Component `yaml:"components,omitempty"`
 	}
 
 	fileConfig := FileConfig{}
@@ -75,10 +141,62 @@ func NewConfigFromBytes(bytes []byte) (Config, error) {
 	if fileConfig.Changelog != nil {
 		config.Changelog = *fileConfig.Changelog
 	}
+	config.Components = fileConfig.Components
+
+	if err := config.Release.TagFormat.Validate(); err != nil {
+		return Config{}, err
+	}
+	if _, err := config.VersionScheme(); err != nil {
+		return Config{}, err
+	}
+	for _, file := range config.Release.VersionFiles {
+		if err := file.Validate(); err != nil {
+			return Config{}, err
+		}
+	}
+	if config.Changelog.Format != "" && config.Changelog.Format != KeepAChangelogFormat {
+		return Config{}, fmt.Errorf("Invalid changelog format (%s), available formats: %s", config.Changelog.Format, KeepAChangelogFormat)
+	}
+	if config.Changelog.DataPath != "" {
+		if _, err := changelogDataFormat(config.Changelog); err != nil {
+			return Config{}, err
+		}
+	}
+	for _, notes := range config.Changelog.StoreNotes {
+		if err := notes.Validate(); err != nil {
+			return Config{}, err
+		}
+	}
+	changelogComponents := map[string]string{}
+	for _, component := range config.Components {
+		if err := component.Validate(); err != nil {
+			return Config{}, err
+		}
+		if other, ok := changelogComponents[component.ChangelogPath]; ok {
+			return Config{}, fmt.Errorf("Invalid components (%s, %s): the components can not share a changelog (%s)", other, component.Name, component.ChangelogPath)
+		}
+		changelogComponents[component.ChangelogPath] = component.Name
+
+		for _, file := range component.VersionFiles {
+			if err := file.Validate(); err != nil {
+				return Config{}, err
+			}
+		}
+	}
 
 	return config, nil
 }
 
+// VersionScheme returns the versioning scheme of the releases.
+func (config Config) VersionScheme() (versioning.Scheme, error) {
+	return versioning.New(config.Release.VersionScheme, config.Release.CalVerFormat)
+}
+
+// ReleaseTag returns the tag of the release version.
+func (config Config) ReleaseTag() string {
+	return config.Release.TagFormat.Tag(config.Release.Version)
+}
+
 // PrintMode ...
 type PrintMode uint8
 
@@ -96,6 +214,9 @@ func (config Config) Print(mode PrintMode) {
 	fmt.Println()
 	log.Infof("Your configuration:")
 
+	if config.Component != nil {
+		log.Infof(" * Component: %s (%s)", config.Component.Name, strings.Join(config.Component.Paths, ", "))
+	}
 	if mode == ChangelogMode || mode == ReleaseMode || mode == FullMode {
 		log.Infof(" * Development branch: %s", config.Release.DevelopmentBranch)
 	}
@@ -105,9 +226,42 @@ func (config Config) Print(mode PrintMode) {
 	if config.Release.Version != "" && (mode == ChangelogMode || mode == ReleaseMode || mode == FullMode) {
 		log.Infof(" * Release version: %s", config.Release.Version)
 	}
+	if len(config.Release.VersionFiles) > 0 {
+		paths := []string{}
+		for _, file := range config.Release.VersionFiles {
+			paths = append(paths, file.Path)
+		}
+		log.Infof(" * Version files: %s", strings.Join(paths, ", "))
+	}
+	if config.Release.VersionScheme == versioning.CalVerScheme {
+		log.Infof(" * Version scheme: %s (%s)", config.Release.VersionScheme, config.Release.CalVerFormat)
+	}
+	if config.Release.Version != "" && config.Release.TagFormat != "" && (mode == ReleaseMode || mode == FullMode) {
+		log.Infof(" * Release tag: %s", config.ReleaseTag())
+	}
 	if mode == ChangelogMode || mode == FullMode {
 		log.Infof(" * Changelog path: %s", config.Changelog.Path)
 	}
+	if config.Changelog.Format != "" && (mode == ChangelogMode || mode == FullMode) {
+		log.Infof(" * Changelog format: %s", config.Changelog.Format)
+	}
+	if len(config.Changelog.IncludePaths) > 0 && (mode == ChangelogMode || mode == FullMode) {
+		log.Infof(" * Changelog include paths: %s", strings.Join(config.Changelog.IncludePaths, ", "))
+	}
+	if len(config.Changelog.ExcludePaths) > 0 && (mode == ChangelogMode || mode == FullMode) {
+		log.Infof(" * Changelog exclude paths: %s", strings.Join(config.Changelog.ExcludePaths, ", "))
+	}
+	if config.Changelog.DataPath != "" && (mode == ChangelogMode || mode == FullMode) {
+		log.Infof(" * Changelog data path: %s", config.Changelog.DataPath)
+	}
+	if mode == ChangelogMode || mode == FullMode {
+		for _, notes := range config.Changelog.StoreNotes {
+			log.Infof(" * Store notes: %s (%s)", notes.Store, strings.Join(notes.Locales, ", "))
+		}
+	}
+	if config.Release.Push && (mode == ReleaseMode || mode == FullMode) {
+		log.Infof(" * Push to remote: %s", config.Release.Remote)
+	}
 
 	fmt.Println()
 }
@@ -46,4 +46,44 @@ changelog:
 	require.Equal(t, nil, err)
 
 	require.Equal(t, "./_changelog/changelog.md", config.Changelog.Path)
+
+	configStr = `
+changelog:
+  path: "./_changelog/changelog.md"
+  sections:
+  - title: Breaking Changes
+    breaking: true
+  - title: Features
+    types:
+    - feat
+  - title: Bug Fixes
+    types: [fix, perf]
+`
+
+	config, err = NewConfigFromBytes([]byte(configStr))
+	require.Equal(t, nil, err)
+
+	require.Equal(t, []ChangelogSection{
+		ChangelogSection{Title: "Breaking Changes", Breaking: true},
+		ChangelogSection{Title: "Features", Types: []string{"feat"}},
+		ChangelogSection{Title: "Bug Fixes", Types: []string{"fix", "perf"}},
+	}, config.Changelog.Sections)
+
+	configStr = `
+release:
+  development_branch: develop
+  release_branch: master
+  version: 1.1.0
+  tag_format: v{{.Version}}
+`
+	config, err = NewConfigFromBytes([]byte(configStr))
+	require.Equal(t, nil, err)
+	require.Equal(t, "v1.1.0", config.ReleaseTag())
+
+	configStr = `
+release:
+  tag_format: mobile
+`
+	_, err = NewConfigFromBytes([]byte(configStr))
+	require.Error(t, err)
 }
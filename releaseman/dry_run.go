@@ -0,0 +1,130 @@
+package releaseman
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+//=======================================
+// Consts
+//=======================================
+
+var (
+	// IsDryRun makes releaseman print the diff of the files, instead of writing them.
+	IsDryRun = false
+
+	dryRunChangedFiles = []string{}
+	// dryRunContents are the contents the files would have, if it was not a dry run
+	dryRunContents = map[string]string{}
+	createdFiles   = []string{}
+)
+
+//=======================================
+// Utility
+//=======================================
+
+// diffLines splits the content into lines, keeping the line endings.
+func diffLines(content string) []string {
+	lines := strings.SplitAfter(content, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// fileDiff returns the unified diff of the file and its new content, a missing file is handled as an empty one.
+func fileDiff(pth, content string) (string, error) {
+	prevContent := ""
+	if exist, err := pathutil.IsPathExists(pth); err != nil {
+		return "", err
+	} else if exist {
+		if prevContent, err = fileutil.ReadStringFromFile(pth); err != nil {
+			return "", err
+		}
+	}
+
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        diffLines(prevContent),
+		B:        diffLines(content),
+		FromFile: "a/" + pth,
+		ToFile:   "b/" + pth,
+		Context:  3,
+	})
+}
+
+// writeFile writes the content to the file, in dry-run mode the diff of the file is printed instead.
+// Zero perm means the permission of a new file created by os.Create.
+func writeFile(pth, content string, perm os.FileMode) error {
+	if !IsDryRun {
+		exist, err := pathutil.IsPathExists(pth)
+		if err != nil {
+			return err
+		}
+		if err := fileutil.WriteStringToFileWithPermission(pth, content, perm); err != nil {
+			return err
+		}
+		if !exist {
+			createdFiles = append(createdFiles, pth)
+		}
+		return nil
+	}
+
+	diff, err := fileDiff(pth, content)
+	if err != nil {
+		return err
+	}
+	dryRunContents[pth] = content
+	if diff == "" {
+		log.Infof("[dry-run] %s is unchanged", pth)
+		return nil
+	}
+
+	log.Infof("[dry-run] write %s", pth)
+	fmt.Print(diff)
+	if !strings.HasSuffix(diff, "\n") {
+		fmt.Println()
+	}
+
+	for _, file := range dryRunChangedFiles {
+		if file == pth {
+			return nil
+		}
+	}
+	dryRunChangedFiles = append(dryRunChangedFiles, pth)
+	return nil
+}
+
+// readFile reads the file, in dry-run mode the content written by the dry run is returned,
+// so the later steps see the changes of the earlier ones (e.g. the incremented build number).
+func readFile(pth string) ([]byte, error) {
+	if content, ok := dryRunContents[pth]; IsDryRun && ok {
+		return []byte(content), nil
+	}
+	return ioutil.ReadFile(pth)
+}
+
+//=======================================
+// Main
+//=======================================
+
+// WriteFile writes the content to the file, in dry-run mode the diff of the file is printed instead.
+func WriteFile(pth, content string) error {
+	return writeFile(pth, content, 0)
+}
+
+// CreatedFiles returns the files created by releaseman (which did not exist before), to remove them if the release fails.
+func CreatedFiles() []string {
+	return append([]string{}, createdFiles...)
+}
+
+// DryRunChangedFiles returns the files which would have been written, if it was not a dry run.
+func DryRunChangedFiles() []string {
+	return append([]string{}, dryRunChangedFiles...)
+}
@@ -0,0 +1,76 @@
+package releaseman
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDryRunWriteFile(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "releaseman")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	pth := filepath.Join(tmpDir, "CHANGELOG.md")
+	require.NoError(t, ioutil.WriteFile(pth, []byte("### 1.0.0\n\n* fix\n"), 0644))
+
+	IsDryRun = true
+	defer func() {
+		IsDryRun = false
+		dryRunChangedFiles = []string{}
+		dryRunContents = map[string]string{}
+	}()
+
+	t.Log("diff of an existing file")
+	{
+		diff, err := fileDiff(pth, "### 1.1.0\n\n* feat\n\n### 1.0.0\n\n* fix\n")
+		require.NoError(t, err)
+		require.Equal(t, "--- a/"+pth+"\n+++ b/"+pth+"\n@@ -1,3 +1,7 @@\n+### 1.1.0\n+\n+* feat\n+\n ### 1.0.0\n \n * fix\n", diff)
+	}
+
+	t.Log("diff of a new file")
+	{
+		diff, err := fileDiff(filepath.Join(tmpDir, "new.md"), "new\n")
+		require.NoError(t, err)
+		require.Contains(t, diff, "@@ -0,0 +1 @@\n+new\n")
+	}
+
+	t.Log("the file is not written in dry-run mode")
+	{
+		require.NoError(t, writeFile(pth, "changed\n", 0))
+		require.NoError(t, writeFile(pth, "changed again\n", 0))
+		require.NoError(t, writeFile(filepath.Join(tmpDir, "unchanged.md"), "", 0))
+
+		content, err := ioutil.ReadFile(pth)
+		require.NoError(t, err)
+		require.Equal(t, "### 1.0.0\n\n* fix\n", string(content))
+		require.Equal(t, []string{pth}, DryRunChangedFiles())
+	}
+
+	t.Log("the content of the dry run is read back")
+	{
+		content, err := readFile(pth)
+		require.NoError(t, err)
+		require.Equal(t, "changed again\n", string(content))
+	}
+}
+
+func TestCreatedFiles(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "releaseman")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+	createdFiles = []string{}
+	defer func() { createdFiles = []string{} }()
+
+	existing := filepath.Join(tmpDir, "CHANGELOG.md")
+	require.NoError(t, ioutil.WriteFile(existing, []byte("### 1.0.0\n"), 0644))
+	created := filepath.Join(tmpDir, "changelog.json")
+
+	require.NoError(t, writeFile(existing, "### 1.1.0\n", 0))
+	require.NoError(t, writeFile(created, "{}", 0))
+	require.NoError(t, writeFile(created, "{}\n", 0))
+	require.Equal(t, []string{created}, CreatedFiles())
+}
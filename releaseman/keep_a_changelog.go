@@ -0,0 +1,198 @@
+package releaseman
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+//=======================================
+// Consts
+//=======================================
+
+// KeepAChangelogFormat is the Keep a Changelog 1.1 layout (https://keepachangelog.com/en/1.1.0/).
+const KeepAChangelogFormat = "keepachangelog"
+
+const unreleasedVersion = "Unreleased"
+
+// KeepAChangelogPreamble is the beginning of a new Keep a Changelog file.
+const KeepAChangelogPreamble = `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.1.0/),
+and this project adheres to [Semantic Versioning](https://semver.org/spec/v2.0.0.html).
+`
+
+// KeepAChangelogReleaseTemplate renders the body of a release section, the data is a ChangelogContentItemModel.
+const KeepAChangelogReleaseTemplate = `{{range .Sections}}### {{.Title}}
+
+{{range .Commits}}- {{if .Breaking}}**BREAKING:** {{end}}{{if .Scope}}**{{.Scope}}:** {{end}}{{.Description}}
+{{end}}
+{{end}}`
+
+// KeepAChangelogSections are the default sections of the Keep a Changelog format,
+// the commits matching no other section (e.g. not conventional commits) are listed under Changed.
+var KeepAChangelogSections = []ChangelogSection{
+	ChangelogSection{
+		Title: "Added",
+		Types: []string{"feat"},
+	},
+	ChangelogSection{
+		Title: "Removed",
+		Types: []string{"revert"},
+	},
+	ChangelogSection{
+		Title: "Fixed",
+		Types: []string{"fix"},
+	},
+	ChangelogSection{
+		Title: "Changed",
+	},
+}
+
+var keepAChangelogCompareRegexp = regexp.MustCompile(`/compare/(.+?)\.\.\.`)
+
+//=======================================
+// Utility
+//=======================================
+
+// keepAChangelogLinks removes the link reference definitions of the releases from the footer,
+// as they are regenerated, and returns the tags the releases were compared to.
+func keepAChangelogLinks(document *ChangelogDocument) map[string]string {
+	previousTags := map[string]string{}
+
+	footer := []string{}
+	for _, line := range strings.Split(document.Footer, "\n") {
+		match := markdownLinkDefinitionRegexp.FindStringSubmatch(line)
+		if match == nil {
+			footer = append(footer, line)
+			continue
+		}
+
+		version := match[1]
+		if strings.EqualFold(version, unreleasedVersion) {
+			version = ""
+		}
+		if document.Release(version) == -1 {
+			footer = append(footer, line)
+			continue
+		}
+		if compareMatch := keepAChangelogCompareRegexp.FindStringSubmatch(match[2]); compareMatch != nil {
+			previousTags[version] = compareMatch[1]
+		}
+	}
+	document.Footer = strings.TrimSpace(strings.Join(footer, "\n"))
+
+	return previousTags
+}
+
+// keepAChangelogCompareLinks returns the reference-style links of the releases: the Unreleased section and every release
+// is compared to the previous release (or the tag it was compared to), the first release links to its tag.
+func keepAChangelogCompareLinks(document ChangelogDocument, previousTags map[string]string, config Config) []string {
+	repositoryURL := strings.TrimSuffix(config.Changelog.RepositoryURL, "/")
+	if repositoryURL == "" {
+		return []string{}
+	}
+
+	links := []string{}
+	for idx, release := range document.Releases {
+		previousTag := previousTags[release.Version]
+		for _, previousRelease := range document.Releases[idx+1:] {
+			if previousRelease.Version != "" {
+				previousTag = config.Release.TagFormat.Tag(previousRelease.Version)
+				break
+			}
+		}
+
+		switch {
+		case release.Version == "" && previousTag != "":
+			links = append(links, fmt.Sprintf("[unreleased]: %s/compare/%s...HEAD", repositoryURL, previousTag))
+		case release.Version == "":
+		case previousTag != "":
+			links = append(links, fmt.Sprintf("[%s]: %s/compare/%s...%s", release.Version, repositoryURL, previousTag, config.Release.TagFormat.Tag(release.Version)))
+		default:
+			links = append(links, fmt.Sprintf("[%s]: %s/releases/tag/%s", release.Version, repositoryURL, config.Release.TagFormat.Tag(release.Version)))
+		}
+	}
+	return links
+}
+
+func newKeepAChangelogRelease(contentItem ChangelogContentItemModel) (ChangelogReleaseSection, error) {
+	releaseTemplate, err := template.New("keepachangelog_release").Funcs(changelogTemplateFuncMap).Parse(KeepAChangelogReleaseTemplate)
+	if err != nil {
+		return ChangelogReleaseSection{}, fmt.Errorf("Failed to parse release template, error: %s", err)
+	}
+
+	var bodyBytes bytes.Buffer
+	if err := releaseTemplate.Execute(&bodyBytes, contentItem); err != nil {
+		return ChangelogReleaseSection{}, fmt.Errorf("Failed to execute release template, error: %s", err)
+	}
+
+	heading := fmt.Sprintf("## [%s]", unreleasedVersion)
+	if contentItem.EndTaggedCommit.Version != "" {
+		heading = fmt.Sprintf("## [%s] - %s", contentItem.EndTaggedCommit.Version, contentItem.EndTaggedCommit.Date.Format("2006-01-02"))
+	}
+
+	return NewChangelogReleaseSection(heading, strings.TrimSpace(bodyBytes.String())), nil
+}
+
+//=======================================
+// Main
+//=======================================
+
+// writeKeepAChangelog writes the releases of the changelog in the Keep a Changelog format,
+// if appendToExisting is true, the releases are merged into the existing changelog.
+// The Unreleased section is always kept at the top: a new release replaces (promotes) it with an empty one.
+func writeKeepAChangelog(changelog ChangelogModel, config Config, appendToExisting bool) error {
+	scheme, err := config.VersionScheme()
+	if err != nil {
+		return err
+	}
+
+	document := ChangelogDocument{Header: strings.TrimSpace(KeepAChangelogPreamble)}
+	previousTags := map[string]string{}
+	if appendToExisting {
+		changelogBytes, err := readFile(config.Changelog.Path)
+		if err != nil {
+			return err
+		}
+		document = ParseChangelogDocument(string(changelogBytes))
+		previousTags = keepAChangelogLinks(&document)
+	}
+
+	// the content items are ordered from the newest, so the oldest is inserted first
+	for idx := len(changelog.ContentItems) - 1; idx >= 0; idx-- {
+		contentItem := changelog.ContentItems[idx]
+
+		release, err := newKeepAChangelogRelease(contentItem)
+		if err != nil {
+			return err
+		}
+		if contentItem.StartTaggedCommit.Tag != "" {
+			previousTags[release.Version] = contentItem.StartTaggedCommit.Tag
+		}
+
+		if release.Version != "" {
+			if unreleasedIdx := document.Release(""); unreleasedIdx != -1 {
+				document.Releases[unreleasedIdx] = NewChangelogReleaseSection(document.Releases[unreleasedIdx].Heading, "")
+			}
+		}
+		if err := document.Upsert(release, scheme); err != nil {
+			return err
+		}
+	}
+
+	if document.Release("") == -1 {
+		if err := document.Insert(NewChangelogReleaseSection(fmt.Sprintf("## [%s]", unreleasedVersion), ""), scheme); err != nil {
+			return err
+		}
+	}
+
+	links := strings.Join(keepAChangelogCompareLinks(document, previousTags, config), "\n")
+	document.Footer = strings.TrimSpace(links + "\n" + document.Footer)
+
+	return writeFile(config.Changelog.Path, document.String(), 0)
+}
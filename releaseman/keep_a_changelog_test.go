@@ -0,0 +1,132 @@
+package releaseman
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bitrise-tools/releaseman/git"
+	"github.com/stretchr/testify/require"
+)
+
+const testKeepAChangelog = `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+## [Unreleased]
+
+### Added
+
+- dark mode
+
+## [1.0.0] - 2026-01-02
+
+### Fixed
+
+- crash on start
+
+[unreleased]: https://github.com/bitrise-tools/releaseman/compare/v1.0.0...HEAD
+[1.0.0]: https://github.com/bitrise-tools/releaseman/releases/tag/v1.0.0
+[docs]: https://github.com/bitrise-tools/releaseman/wiki
+`
+
+func TestKeepAChangelogLinks(t *testing.T) {
+	document := ParseChangelogDocument(testKeepAChangelog)
+	previousTags := keepAChangelogLinks(&document)
+	require.Equal(t, map[string]string{"": "v1.0.0"}, previousTags)
+	require.Equal(t, "[docs]: https://github.com/bitrise-tools/releaseman/wiki", document.Footer)
+
+	config := Config{
+		Release:   Release{TagFormat: "v{{.Version}}"},
+		Changelog: Changelog{RepositoryURL: "https://github.com/bitrise-tools/releaseman/"},
+	}
+	require.Equal(t, []string{
+		"[unreleased]: https://github.com/bitrise-tools/releaseman/compare/v1.0.0...HEAD",
+		"[1.0.0]: https://github.com/bitrise-tools/releaseman/releases/tag/v1.0.0",
+	}, keepAChangelogCompareLinks(document, previousTags, config))
+}
+
+func TestWriteKeepAChangelog(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "releaseman")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, os.RemoveAll(tmpDir))
+	}()
+
+	date := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	taggedCommits := []git.CommitModel{
+		git.CommitModel{Hash: "0", Tag: "v0.9.0", Version: "0.9.0", Date: date},
+		git.CommitModel{Hash: "1", Parents: []string{"0"}, Tag: "v1.0.0", Version: "1.0.0", Date: date},
+	}
+	commits := []git.CommitModel{
+		git.CommitModel{Hash: "4", Parents: []string{"3"}, Message: "update dependencies", Description: "update dependencies"},
+		git.CommitModel{Hash: "3", Parents: []string{"2"}, Type: "fix", Scope: "cli", Description: "exit code"},
+		git.CommitModel{Hash: "2", Parents: []string{"1"}, Type: "feat", Description: "dark mode", Breaking: true},
+		git.CommitModel{Hash: "1", Parents: []string{"0"}, Type: "feat", Description: "first release"},
+		git.CommitModel{Hash: "0"},
+	}
+
+	config := Config{
+		Release: Release{TagFormat: "v{{.Version}}"},
+		Changelog: Changelog{
+			Path:          filepath.Join(tmpDir, "CHANGELOG.md"),
+			Format:        KeepAChangelogFormat,
+			RepositoryURL: "https://github.com/bitrise-tools/releaseman",
+		},
+	}
+	readChangelog := func() string {
+		content, err := ioutil.ReadFile(config.Changelog.Path)
+		require.NoError(t, err)
+		return string(content)
+	}
+
+	t.Log("unreleased")
+	{
+		require.NoError(t, WriteChangelog(commits, taggedCommits, config, false))
+		require.Equal(t, KeepAChangelogPreamble+`
+## [Unreleased]
+
+### Added
+
+- **BREAKING:** dark mode
+
+### Fixed
+
+- **cli:** exit code
+
+### Changed
+
+- update dependencies
+
+## [1.0.0] - 2026-10-16
+
+### Added
+
+- first release
+
+[unreleased]: https://github.com/bitrise-tools/releaseman/compare/v1.0.0...HEAD
+[1.0.0]: https://github.com/bitrise-tools/releaseman/compare/v0.9.0...v1.0.0
+`, readChangelog())
+
+		// updating the unreleased section does not change the releases
+		require.NoError(t, WriteChangelog(commits[1:2], taggedCommits[1:], config, true))
+		require.Contains(t, readChangelog(), "## [Unreleased]\n\n### Fixed\n\n- **cli:** exit code\n\n## [1.0.0] - 2026-10-16\n")
+	}
+
+	t.Log("release promotes the unreleased section")
+	{
+		config.Release.Version = "1.1.0"
+		require.NoError(t, WriteChangelog(commits[1:3], taggedCommits[1:], config, true))
+
+		changelog := readChangelog()
+		require.Contains(t, changelog, "## [Unreleased]\n\n## [1.1.0] - ")
+		require.Contains(t, changelog, "### Added\n\n- **BREAKING:** dark mode\n\n### Fixed\n\n- **cli:** exit code\n\n## [1.0.0] - 2026-10-16\n")
+		require.Contains(t, changelog, `
+[unreleased]: https://github.com/bitrise-tools/releaseman/compare/v1.1.0...HEAD
+[1.1.0]: https://github.com/bitrise-tools/releaseman/compare/v1.0.0...v1.1.0
+[1.0.0]: https://github.com/bitrise-tools/releaseman/compare/v0.9.0...v1.0.0
+`)
+	}
+}
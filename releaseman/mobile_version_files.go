@@ -0,0 +1,90 @@
+package releaseman
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+//=======================================
+// Consts
+//=======================================
+
+const (
+	// PlistVersionFile is an Info.plist: CFBundleShortVersionString and CFBundleVersion
+	PlistVersionFile = "plist"
+	// PbxprojVersionFile is an Xcode project.pbxproj: MARKETING_VERSION and CURRENT_PROJECT_VERSION build settings
+	PbxprojVersionFile = "pbxproj"
+	// GradleVersionFile is an Android build.gradle(.kts): versionName and versionCode
+	GradleVersionFile = "gradle"
+)
+
+// The build setting references, like $(MARKETING_VERSION), are not versions: the referred build setting is updated.
+var (
+	mobileVersionRegexps = map[string]*regexp.Regexp{
+		PlistVersionFile:   regexp.MustCompile(`<key>CFBundleShortVersionString</key>\s*<string>([^<$][^<]*)</string>`),
+		PbxprojVersionFile: regexp.MustCompile(`(?m)^\s*MARKETING_VERSION = "?([^"$;\s]+)"?;`),
+		GradleVersionFile:  regexp.MustCompile(`(?m)^\s*versionName\s*(?:=\s*)?\(?["']([^"']*)["']`),
+	}
+
+	mobileBuildNumberRegexps = map[string]*regexp.Regexp{
+		PlistVersionFile:   regexp.MustCompile(`<key>CFBundleVersion</key>\s*<string>([^<$][^<]*)</string>`),
+		PbxprojVersionFile: regexp.MustCompile(`(?m)^\s*CURRENT_PROJECT_VERSION = "?([^"$;\s]+)"?;`),
+		GradleVersionFile:  regexp.MustCompile(`(?m)^\s*versionCode\s*(?:=\s*)?\(?(\d+)`),
+	}
+)
+
+//=======================================
+// Utility
+//=======================================
+
+// incrementedBuildNumbers returns the replacements of the build numbers of a mobile project file,
+// every build number is replaced with the highest one incremented.
+func (file VersionFile) incrementedBuildNumbers(content string) ([]replacement, error) {
+	re, ok := mobileBuildNumberRegexps[file.fileType()]
+	if !ok {
+		return []replacement{}, nil
+	}
+
+	locs := submatchLocations(re, content, 1)
+
+	buildNumber := int64(0)
+	for _, loc := range locs {
+		number, err := strconv.ParseInt(content[loc[0]:loc[1]], 10, 64)
+		if err != nil {
+			return []replacement{}, fmt.Errorf("Invalid build number (%s) in %s, only integer build numbers can be incremented", content[loc[0]:loc[1]], file.Path)
+		}
+		if number > buildNumber {
+			buildNumber = number
+		}
+	}
+
+	replacements := []replacement{}
+	for _, loc := range locs {
+		replacements = append(replacements, replacement{start: loc[0], end: loc[1], value: strconv.FormatInt(buildNumber+1, 10)})
+	}
+	return replacements, nil
+}
+
+//=======================================
+// Main
+//=======================================
+
+// ReadBuildNumber returns the build number of a mobile project file, empty if the file has no build number.
+func (file VersionFile) ReadBuildNumber() (string, error) {
+	re, ok := mobileBuildNumberRegexps[file.fileType()]
+	if !ok {
+		return "", nil
+	}
+
+	contentBytes, err := readFile(file.Path)
+	if err != nil {
+		return "", err
+	}
+	content := string(contentBytes)
+
+	if loc, ok := submatchLocation(re, content, 1); ok {
+		return content[loc[0]:loc[1]], nil
+	}
+	return "", nil
+}
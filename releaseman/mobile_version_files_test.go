@@ -0,0 +1,165 @@
+package releaseman
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testInfoPlist = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>CFBundleName</key>
+	<string>App</string>
+	<key>CFBundleShortVersionString</key>
+	<string>1.2.0</string>
+	<key>CFBundleVersion</key>
+	<string>41</string>
+</dict>
+</plist>
+`
+
+const testPbxproj = `		1D6058940D05DD3E006BFB54 /* Debug */ = {
+			isa = XCBuildConfiguration;
+			buildSettings = {
+				CURRENT_PROJECT_VERSION = 41;
+				INFOPLIST_FILE = "App/Info.plist";
+				MARKETING_VERSION = 1.2.0;
+			};
+			name = Debug;
+		};
+		1D6058950D05DD3E006BFB54 /* Release */ = {
+			isa = XCBuildConfiguration;
+			buildSettings = {
+				CURRENT_PROJECT_VERSION = 42;
+				INFOPLIST_FILE = "App/Info.plist";
+				MARKETING_VERSION = "1.2.0";
+			};
+			name = Release;
+		};
+`
+
+const testBuildGradle = `android {
+    defaultConfig {
+        applicationId "io.bitrise.app"
+        minSdkVersion 21
+        versionCode 41
+        versionName "1.2.0"
+    }
+}
+`
+
+const testBuildGradleKts = `android {
+    defaultConfig {
+        applicationId = "io.bitrise.app"
+        versionCode = 41
+        versionName = "1.2.0"
+    }
+}
+`
+
+func TestMobileVersionFiles(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "releaseman")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, os.RemoveAll(tmpDir))
+	}()
+
+	writeFile := func(pth, content string) string {
+		pth = filepath.Join(tmpDir, pth)
+		require.NoError(t, os.MkdirAll(filepath.Dir(pth), 0755))
+		require.NoError(t, ioutil.WriteFile(pth, []byte(content), 0644))
+		return pth
+	}
+	readFile := func(pth string) string {
+		content, err := ioutil.ReadFile(pth)
+		require.NoError(t, err)
+		return string(content)
+	}
+
+	t.Log("Info.plist")
+	{
+		file := VersionFile{Path: writeFile("App/Info.plist", testInfoPlist)}
+		require.Equal(t, PlistVersionFile, file.fileType())
+
+		version, err := file.ReadVersion()
+		require.NoError(t, err)
+		require.Equal(t, "1.2.0", version)
+
+		require.NoError(t, file.WriteVersion("1.3.0"))
+		require.Contains(t, readFile(file.Path), "<key>CFBundleShortVersionString</key>\n\t<string>1.3.0</string>")
+		require.Contains(t, readFile(file.Path), "<key>CFBundleVersion</key>\n\t<string>42</string>")
+
+		buildNumber, err := file.ReadBuildNumber()
+		require.NoError(t, err)
+		require.Equal(t, "42", buildNumber)
+	}
+
+	t.Log("Info.plist referring to the build settings")
+	{
+		content := "<dict>\n\t<key>CFBundleShortVersionString</key>\n\t<string>$(MARKETING_VERSION)</string>\n</dict>\n"
+		file := VersionFile{Path: writeFile("Ext/Info.plist", content)}
+		_, err := file.ReadVersion()
+		require.Error(t, err)
+	}
+
+	t.Log("project.pbxproj")
+	{
+		file := VersionFile{Path: writeFile("App.xcodeproj/project.pbxproj", testPbxproj)}
+		require.Equal(t, PbxprojVersionFile, file.fileType())
+
+		version, err := file.ReadVersion()
+		require.NoError(t, err)
+		require.Equal(t, "1.2.0", version)
+
+		require.NoError(t, file.WriteVersion("1.3.0"))
+		content := readFile(file.Path)
+		require.Contains(t, content, "CURRENT_PROJECT_VERSION = 43;\n\t\t\t\tINFOPLIST_FILE = \"App/Info.plist\";\n\t\t\t\tMARKETING_VERSION = 1.3.0;")
+		require.Contains(t, content, "CURRENT_PROJECT_VERSION = 43;\n\t\t\t\tINFOPLIST_FILE = \"App/Info.plist\";\n\t\t\t\tMARKETING_VERSION = \"1.3.0\";")
+	}
+
+	t.Log("project.pbxproj out of sync")
+	{
+		file := VersionFile{Path: writeFile("Other.xcodeproj/project.pbxproj", testPbxproj+"\t\t\t\tMARKETING_VERSION = 1.1.0;\n")}
+		_, err := file.ReadVersion()
+		require.Error(t, err)
+	}
+
+	t.Log("build.gradle")
+	{
+		file := VersionFile{Path: writeFile("app/build.gradle", testBuildGradle)}
+		require.Equal(t, GradleVersionFile, file.fileType())
+
+		version, err := file.ReadVersion()
+		require.NoError(t, err)
+		require.Equal(t, "1.2.0", version)
+
+		require.NoError(t, file.WriteVersion("1.3.0"))
+		require.Contains(t, readFile(file.Path), "        versionCode 42\n        versionName \"1.3.0\"\n")
+	}
+
+	t.Log("build.gradle.kts")
+	{
+		file := VersionFile{Path: writeFile("app/build.gradle.kts", testBuildGradleKts)}
+		require.Equal(t, GradleVersionFile, file.fileType())
+
+		require.NoError(t, file.WriteVersion("1.3.0"))
+		require.Contains(t, readFile(file.Path), "        versionCode = 42\n        versionName = \"1.3.0\"\n")
+
+		buildNumber, err := file.ReadBuildNumber()
+		require.NoError(t, err)
+		require.Equal(t, "42", buildNumber)
+	}
+
+	t.Log("invalid build number")
+	{
+		content := "<key>CFBundleShortVersionString</key>\n<string>1.2.0</string>\n<key>CFBundleVersion</key>\n<string>1.2.0.41</string>\n"
+		file := VersionFile{Path: writeFile("Invalid/Info.plist", content)}
+		require.Error(t, file.WriteVersion("1.3.0"))
+		require.Equal(t, content, readFile(file.Path))
+	}
+}
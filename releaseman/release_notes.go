@@ -0,0 +1,70 @@
+package releaseman
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/bitrise-tools/releaseman/git"
+)
+
+//=======================================
+// Consts
+//=======================================
+
+// ReleaseNotesTemplate is the default template of a single release's notes (and of the annotated tag message).
+// Lines starting with # are avoided, as git strips them from tag messages.
+const ReleaseNotesTemplate = `Release {{.Version}}
+{{range .Sections}}
+{{.Title}}:
+{{range .Commits}}* {{if .Scope}}{{.Scope}}: {{end}}{{.Description}}
+{{end}}{{end}}`
+
+//=======================================
+// Models
+//=======================================
+
+// ReleaseNotesModel is the model of a single release's notes.
+type ReleaseNotesModel struct {
+	Version string
+	ChangelogContentItemModel
+}
+
+//=======================================
+// Main
+//=======================================
+
+// NewReleaseNotes collects the commits of the release, made since the lastTaggedCommit (nil means first release).
+func NewReleaseNotes(commits []git.CommitModel, lastTaggedCommit *git.CommitModel, config Config) ReleaseNotesModel {
+	taggedCommits := []git.CommitModel{}
+	if lastTaggedCommit != nil {
+		taggedCommits = append(taggedCommits, *lastTaggedCommit)
+	}
+
+	changelog := generateChangelogContent(commits, taggedCommits, config)
+
+	return ReleaseNotesModel{
+		Version:                   config.Release.Version,
+		ChangelogContentItemModel: changelog.ContentItems[0],
+	}
+}
+
+// RenderReleaseNotes renders the release notes with the given template (ReleaseNotesTemplate if empty).
+func RenderReleaseNotes(releaseNotes ReleaseNotesModel, templateStr string) (string, error) {
+	if templateStr == "" {
+		templateStr = ReleaseNotesTemplate
+	}
+
+	releaseNotesTemplate, err := template.New("release_notes").Funcs(changelogTemplateFuncMap).Parse(templateStr)
+	if err != nil {
+		return "", fmt.Errorf("Failed to parse release notes template, error: %s", err)
+	}
+
+	var releaseNotesBytes bytes.Buffer
+	if err := releaseNotesTemplate.Execute(&releaseNotesBytes, releaseNotes); err != nil {
+		return "", fmt.Errorf("Failed to execute release notes template, error: %s", err)
+	}
+
+	return strings.TrimSpace(releaseNotesBytes.String()), nil
+}
@@ -0,0 +1,51 @@
+package releaseman
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bitrise-tools/releaseman/git"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderReleaseNotes(t *testing.T) {
+	lastTaggedCommit := git.CommitModel{Hash: "1", Tag: "1.0.0", Date: time.Unix(1454498663, 0)}
+	commits := []git.CommitModel{
+		git.CommitModel{Hash: "4", Parents: []string{"3"}, Type: "fix", Scope: "cli", Description: "exit code"},
+		git.CommitModel{Hash: "3", Parents: []string{"2"}, Message: "Update README", Description: "Update README"},
+		git.CommitModel{Hash: "2", Parents: []string{"1"}, Type: "feat", Description: "annotated tags"},
+		lastTaggedCommit,
+	}
+	config := Config{Release: Release{Version: "1.1.0"}}
+
+	releaseNotes := NewReleaseNotes(commits, &lastTaggedCommit, config)
+	require.Equal(t, "1.1.0", releaseNotes.Version)
+	require.Equal(t, "1.0.0", releaseNotes.StartTaggedCommit.Tag)
+	require.Equal(t, 3, len(releaseNotes.Commits))
+
+	t.Log("default template")
+	{
+		notes, err := RenderReleaseNotes(releaseNotes, "")
+		require.NoError(t, err)
+		require.Equal(t, "Release 1.1.0\n\nFeatures:\n* annotated tags\n\nBug Fixes:\n* cli: exit code", notes)
+	}
+
+	t.Log("custom template")
+	{
+		notes, err := RenderReleaseNotes(releaseNotes, "v{{.Version}} ({{len .Commits}} commits since {{.StartTaggedCommit.Tag}})\n")
+		require.NoError(t, err)
+		require.Equal(t, "v1.1.0 (3 commits since 1.0.0)", notes)
+	}
+
+	t.Log("invalid template")
+	{
+		_, err := RenderReleaseNotes(releaseNotes, "{{.Missing")
+		require.Error(t, err)
+	}
+
+	t.Log("first release")
+	{
+		releaseNotes := NewReleaseNotes(commits, nil, config)
+		require.Equal(t, 4, len(releaseNotes.Commits))
+	}
+}
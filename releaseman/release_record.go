@@ -0,0 +1,83 @@
+package releaseman
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/bitrise-tools/releaseman/git"
+)
+
+//=======================================
+// Consts
+//=======================================
+
+const (
+	// ReleaseRecordFileName is the path of the release record, relative to the .git directory
+	ReleaseRecordFileName = "releaseman/last_release.json"
+)
+
+//=======================================
+// Models
+//=======================================
+
+// ReleaseRecord describes the last local release, it is used to roll back the release.
+type ReleaseRecord struct {
+	Version       string `json:"version"`
+	Tag           string `json:"tag"`
+	TagCommit     string `json:"tag_commit"`
+	ChangelogPath string `json:"changelog_path,omitempty"`
+	// ChangelogExisted and Changelog are the changelog before the release, it is restored by the rollback
+	ChangelogExisted bool   `json:"changelog_existed,omitempty"`
+	Changelog        string `json:"changelog,omitempty"`
+	// Before is the state of the repository before the release
+	Before git.RepositoryState `json:"before"`
+	// After is the state of the repository right after the release
+	After git.RepositoryState `json:"after"`
+}
+
+//=======================================
+// Main
+//=======================================
+
+// ReleaseRecordPath returns the path of the release record in the given .git directory.
+func ReleaseRecordPath(gitDir string) string {
+	return filepath.Join(gitDir, ReleaseRecordFileName)
+}
+
+// WriteReleaseRecord ...
+func WriteReleaseRecord(pth string, record ReleaseRecord) error {
+	if err := pathutil.EnsureDirExist(filepath.Dir(pth)); err != nil {
+		return err
+	}
+
+	bytes, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return err
+	}
+	return fileutil.WriteBytesToFile(pth, bytes)
+}
+
+// ReadReleaseRecord ...
+func ReadReleaseRecord(pth string) (ReleaseRecord, error) {
+	bytes, err := fileutil.ReadBytesFromFile(pth)
+	if err != nil {
+		return ReleaseRecord{}, err
+	}
+
+	record := ReleaseRecord{}
+	if err := json.Unmarshal(bytes, &record); err != nil {
+		return ReleaseRecord{}, err
+	}
+	return record, nil
+}
+
+// RemoveReleaseRecord ...
+func RemoveReleaseRecord(pth string) error {
+	if err := os.Remove(pth); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
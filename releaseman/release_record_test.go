@@ -0,0 +1,51 @@
+package releaseman
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-tools/releaseman/git"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReleaseRecord(t *testing.T) {
+	gitDir, err := ioutil.TempDir("", "releaseman")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, os.RemoveAll(gitDir))
+	}()
+
+	pth := ReleaseRecordPath(gitDir)
+	require.Equal(t, filepath.Join(gitDir, "releaseman", "last_release.json"), pth)
+
+	record := ReleaseRecord{
+		Version:          "1.1.0",
+		Tag:              "1.1.0",
+		TagCommit:        "c8b4aa1",
+		ChangelogPath:    "CHANGELOG.md",
+		ChangelogExisted: true,
+		Changelog:        "### 1.0.0\n\n* fix\n",
+		Before: git.RepositoryState{
+			CurrentBranch: "develop",
+			BranchHeads:   map[string]string{"develop": "4d2f4ac", "master": "bd207c8"},
+			Tags:          []string{"1.0.0"},
+		},
+		After: git.RepositoryState{
+			CurrentBranch: "develop",
+			BranchHeads:   map[string]string{"develop": "1878297", "master": "c8b4aa1"},
+			Tags:          []string{"1.0.0", "1.1.0"},
+		},
+	}
+	require.NoError(t, WriteReleaseRecord(pth, record))
+
+	read, err := ReadReleaseRecord(pth)
+	require.NoError(t, err)
+	require.Equal(t, record, read)
+
+	require.NoError(t, RemoveReleaseRecord(pth))
+	require.NoError(t, RemoveReleaseRecord(pth))
+	_, err = ReadReleaseRecord(pth)
+	require.Error(t, err)
+}
@@ -0,0 +1,251 @@
+package releaseman
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+
+	"github.com/bitrise-tools/releaseman/git"
+)
+
+//=======================================
+// Consts
+//=======================================
+
+const (
+	// AndroidStore writes the notes into the fastlane supply layout: <metadata_path>/<locale>/changelogs/<versionCode>.txt
+	AndroidStore = "android"
+	// IOSStore writes the notes into the fastlane deliver layout: <metadata_path>/<locale>/release_notes.txt
+	IOSStore = "ios"
+)
+
+var (
+	storeMetadataPaths = map[string]string{
+		AndroidStore: "fastlane/metadata/android",
+		IOSStore:     "fastlane/metadata",
+	}
+
+	// storeMaxLengths are the character limits of the release notes in Google Play and in the App Store
+	storeMaxLengths = map[string]int{
+		AndroidStore: 500,
+		IOSStore:     4000,
+	}
+)
+
+// StoreNotesTemplate is the default template of the store release notes.
+const StoreNotesTemplate = `{{range .Sections}}{{.Title}}:
+{{range .Commits}}• {{.Description}}
+{{end}}{{if .Omitted}}• and {{.Omitted}} more
+{{end}}
+{{end}}`
+
+const truncationMark = "…"
+
+// otherChangesTitle is the title of the store notes section of the commits matching no changelog section.
+const otherChangesTitle = "Other Changes"
+
+//=======================================
+// Models
+//=======================================
+
+// StoreNotes configures the release notes of an app store, written for every locale.
+type StoreNotes struct {
+	// Store is android or ios
+	Store   string   `yaml:"store"`
+	Locales []string `yaml:"locales"`
+	// MetadataPath is the fastlane metadata directory of the store
+	MetadataPath string `yaml:"metadata_path,omitempty"`
+	// Template is rendered with the StoreNotesModel, defaults to StoreNotesTemplate
+	Template string `yaml:"template,omitempty"`
+	// MaxLength is the character limit of the notes, defaults to the limit of the store
+	MaxLength int `yaml:"max_length,omitempty"`
+}
+
+// StoreNotesSectionModel is a section of the store notes,
+// the commits not fitting into the character limit are counted as Omitted.
+type StoreNotesSectionModel struct {
+	Title   string
+	Commits []git.CommitModel
+	Omitted int
+}
+
+// StoreNotesModel ...
+type StoreNotesModel struct {
+	Version  string
+	Locale   string
+	Sections []StoreNotesSectionModel
+}
+
+//=======================================
+// Utility
+//=======================================
+
+func (notes StoreNotes) metadataPath() string {
+	if notes.MetadataPath != "" {
+		return notes.MetadataPath
+	}
+	return storeMetadataPaths[notes.Store]
+}
+
+func (notes StoreNotes) maxLength() int {
+	if notes.MaxLength > 0 {
+		return notes.MaxLength
+	}
+	return storeMaxLengths[notes.Store]
+}
+
+// notesPath returns the path of the notes of the locale.
+func (notes StoreNotes) notesPath(locale, versionCode string) string {
+	if notes.Store == AndroidStore {
+		return filepath.Join(notes.metadataPath(), locale, "changelogs", versionCode+".txt")
+	}
+	return filepath.Join(notes.metadataPath(), locale, "release_notes.txt")
+}
+
+func renderStoreNotes(model StoreNotesModel, templateStr string) (string, error) {
+	notesTemplate, err := template.New("store_notes").Funcs(changelogTemplateFuncMap).Parse(templateStr)
+	if err != nil {
+		return "", fmt.Errorf("Failed to parse store notes template, error: %s", err)
+	}
+
+	var notesBytes bytes.Buffer
+	if err := notesTemplate.Execute(&notesBytes, model); err != nil {
+		return "", fmt.Errorf("Failed to execute store notes template, error: %s", err)
+	}
+	return strings.TrimSpace(notesBytes.String()), nil
+}
+
+// truncate cuts the text to the max length (in characters), marking the cut.
+func truncate(text string, maxLength int) string {
+	if utf8.RuneCountInString(text) <= maxLength {
+		return text
+	}
+	runes := []rune(text)
+	return strings.TrimSpace(string(runes[:maxLength-utf8.RuneCountInString(truncationMark)])) + truncationMark
+}
+
+// summarizeStoreNotes renders the notes within the max length: the last commits of the last sections
+// are summarized as omitted ones, while the notes are too long. If even the summary is too long, it is truncated.
+func summarizeStoreNotes(model StoreNotesModel, templateStr string, maxLength int) (string, error) {
+	for {
+		notes, err := renderStoreNotes(model, templateStr)
+		if err != nil {
+			return "", err
+		}
+		if maxLength <= 0 || utf8.RuneCountInString(notes) <= maxLength {
+			return notes, nil
+		}
+
+		summarized := false
+		for idx := len(model.Sections) - 1; idx >= 0 && !summarized; idx-- {
+			section := &model.Sections[idx]
+			if len(section.Commits) > 0 {
+				section.Commits = section.Commits[:len(section.Commits)-1]
+				section.Omitted++
+				summarized = true
+			}
+		}
+		if !summarized {
+			return truncate(notes, maxLength), nil
+		}
+	}
+}
+
+//=======================================
+// Main
+//=======================================
+
+// Validate ...
+func (notes StoreNotes) Validate() error {
+	if notes.Store != AndroidStore && notes.Store != IOSStore {
+		return fmt.Errorf("Invalid store notes: unknown store (%s), available stores: %s, %s", notes.Store, AndroidStore, IOSStore)
+	}
+	if len(notes.Locales) == 0 {
+		return fmt.Errorf("Invalid %s store notes: missing locales", notes.Store)
+	}
+	return nil
+}
+
+// NewStoreNotesModel returns the store notes model of the release notes, with copies of the sections,
+// so the notes of every store can be summarized independently.
+// The commits matching no section are listed in a last "Other Changes" section, so they are summarized first.
+func NewStoreNotesModel(releaseNotes ReleaseNotesModel, locale string) StoreNotesModel {
+	sections := []StoreNotesSectionModel{}
+	sectionCommits := map[string]bool{}
+	for _, section := range releaseNotes.Sections {
+		sections = append(sections, StoreNotesSectionModel{
+			Title:   section.Title,
+			Commits: append([]git.CommitModel{}, section.Commits...),
+		})
+		for _, commit := range section.Commits {
+			sectionCommits[commit.Hash] = true
+		}
+	}
+
+	otherCommits := []git.CommitModel{}
+	for _, commit := range releaseNotes.Commits {
+		if !sectionCommits[commit.Hash] && commit.Hash != releaseNotes.StartTaggedCommit.Hash {
+			otherCommits = append(otherCommits, commit)
+		}
+	}
+	if len(otherCommits) > 0 {
+		sections = append(sections, StoreNotesSectionModel{
+			Title:   otherChangesTitle,
+			Commits: otherCommits,
+		})
+	}
+
+	return StoreNotesModel{
+		Version:  releaseNotes.Version,
+		Locale:   locale,
+		Sections: sections,
+	}
+}
+
+// AndroidVersionCode returns the versionCode of the first build.gradle of the version files.
+func AndroidVersionCode(files []VersionFile) (string, error) {
+	for _, file := range files {
+		if file.fileType() != GradleVersionFile {
+			continue
+		}
+		return file.ReadBuildNumber()
+	}
+	return "", nil
+}
+
+// WriteStoreNotes writes the release notes of every locale of the store, and returns the written paths.
+// The versionCode names the notes of the android store.
+func WriteStoreNotes(releaseNotes ReleaseNotesModel, notes StoreNotes, versionCode string) ([]string, error) {
+	if notes.Store == AndroidStore && versionCode == "" {
+		return []string{}, fmt.Errorf("The android store notes are named by the versionCode: add the build.gradle to the version files")
+	}
+
+	templateStr := notes.Template
+	if templateStr == "" {
+		templateStr = StoreNotesTemplate
+	}
+
+	paths := []string{}
+	for _, locale := range notes.Locales {
+		content, err := summarizeStoreNotes(NewStoreNotesModel(releaseNotes, locale), templateStr, notes.maxLength())
+		if err != nil {
+			return []string{}, err
+		}
+
+		pth := notes.notesPath(locale, versionCode)
+		if !IsDryRun {
+			if err := os.MkdirAll(filepath.Dir(pth), 0755); err != nil {
+				return []string{}, err
+			}
+		}
+		if err := writeFile(pth, content+"\n", 0); err != nil {
+			return []string{}, err
+		}
+		paths = append(paths, pth)
+	}
+	return paths, nil
+}
@@ -0,0 +1,112 @@
+package releaseman
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/bitrise-tools/releaseman/git"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreNotes(t *testing.T) {
+	lastTaggedCommit := git.CommitModel{Hash: "0", Tag: "1.0.0", Version: "1.0.0"}
+	commits := []git.CommitModel{
+		git.CommitModel{Hash: "4", Parents: []string{"3"}, Type: "fix", Description: "crash on start"},
+		git.CommitModel{Hash: "3", Parents: []string{"2"}, Type: "feat", Description: "dark mode"},
+		git.CommitModel{Hash: "2", Parents: []string{"1"}, Type: "feat", Description: "offline sync"},
+		git.CommitModel{Hash: "1", Parents: []string{"0"}, Type: "chore", Description: "bump dependencies"},
+		lastTaggedCommit,
+	}
+	releaseNotes := NewReleaseNotes(commits, &lastTaggedCommit, Config{Release: Release{Version: "1.1.0"}})
+
+	t.Log("default template")
+	{
+		notes, err := summarizeStoreNotes(NewStoreNotesModel(releaseNotes, "en-US"), StoreNotesTemplate, 500)
+		require.NoError(t, err)
+		require.Equal(t, "Features:\n• dark mode\n• offline sync\n\nBug Fixes:\n• crash on start\n\nOther Changes:\n• bump dependencies", notes)
+	}
+
+	t.Log("commits matching no section")
+	{
+		commits := []git.CommitModel{
+			git.CommitModel{Hash: "2", Parents: []string{"1"}, Message: "Update translations", Description: "Update translations"},
+			git.CommitModel{Hash: "1", Parents: []string{"0"}, Type: "docs", Description: "privacy policy"},
+			lastTaggedCommit,
+		}
+		releaseNotes := NewReleaseNotes(commits, &lastTaggedCommit, Config{Release: Release{Version: "1.0.1"}})
+
+		notes, err := summarizeStoreNotes(NewStoreNotesModel(releaseNotes, "en-US"), StoreNotesTemplate, 500)
+		require.NoError(t, err)
+		require.Equal(t, "Other Changes:\n• Update translations\n• privacy policy", notes)
+	}
+
+	t.Log("summarized from the last section")
+	{
+		notes, err := summarizeStoreNotes(NewStoreNotesModel(releaseNotes, "en-US"), StoreNotesTemplate, 90)
+		require.NoError(t, err)
+		require.Equal(t, "Features:\n• dark mode\n• offline sync\n\nBug Fixes:\n• and 1 more\n\nOther Changes:\n• and 1 more", notes)
+
+		notes, err = summarizeStoreNotes(NewStoreNotesModel(releaseNotes, "en-US"), StoreNotesTemplate, 89)
+		require.NoError(t, err)
+		require.Equal(t, "Features:\n• dark mode\n• and 1 more\n\nBug Fixes:\n• and 1 more\n\nOther Changes:\n• and 1 more", notes)
+
+		// the summaries do not modify the release notes
+		require.Equal(t, 2, len(releaseNotes.Sections[0].Commits))
+	}
+
+	t.Log("truncated")
+	{
+		notes, err := summarizeStoreNotes(NewStoreNotesModel(releaseNotes, "en-US"), StoreNotesTemplate, 20)
+		require.NoError(t, err)
+		require.Equal(t, 20, utf8.RuneCountInString(notes))
+		require.True(t, strings.HasSuffix(notes, truncationMark))
+	}
+
+	t.Log("custom template")
+	{
+		notes, err := summarizeStoreNotes(NewStoreNotesModel(releaseNotes, "hu-HU"), "{{.Locale}} {{.Version}}", 0)
+		require.NoError(t, err)
+		require.Equal(t, "hu-HU 1.1.0", notes)
+	}
+
+	t.Log("write")
+	{
+		tmpDir, err := ioutil.TempDir("", "releaseman")
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, os.RemoveAll(tmpDir))
+		}()
+
+		android := StoreNotes{Store: AndroidStore, Locales: []string{"en-US", "de-DE"}, MetadataPath: filepath.Join(tmpDir, "android")}
+		require.NoError(t, android.Validate())
+
+		_, err = WriteStoreNotes(releaseNotes, android, "")
+		require.Error(t, err)
+
+		paths, err := WriteStoreNotes(releaseNotes, android, "42")
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			filepath.Join(tmpDir, "android", "en-US", "changelogs", "42.txt"),
+			filepath.Join(tmpDir, "android", "de-DE", "changelogs", "42.txt"),
+		}, paths)
+
+		ios := StoreNotes{Store: IOSStore, Locales: []string{"en-US"}, MetadataPath: tmpDir, Template: "{{.Version}}"}
+		paths, err = WriteStoreNotes(releaseNotes, ios, "")
+		require.NoError(t, err)
+		require.Equal(t, []string{filepath.Join(tmpDir, "en-US", "release_notes.txt")}, paths)
+
+		content, err := ioutil.ReadFile(paths[0])
+		require.NoError(t, err)
+		require.Equal(t, "1.1.0\n", string(content))
+	}
+
+	t.Log("invalid")
+	{
+		require.Error(t, StoreNotes{Store: "windows", Locales: []string{"en-US"}}.Validate())
+		require.Error(t, StoreNotes{Store: IOSStore}.Validate())
+	}
+}
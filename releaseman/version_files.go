@@ -0,0 +1,314 @@
+package releaseman
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+//=======================================
+// Consts
+//=======================================
+
+const (
+	// GoVersionFile is a Go source file with a VERSION constant
+	GoVersionFile = "go"
+	// PackageJSONVersionFile is an npm package.json
+	PackageJSONVersionFile = "package.json"
+	// CargoVersionFile is a Rust Cargo.toml
+	CargoVersionFile = "cargo"
+	// PyprojectVersionFile is a Python pyproject.toml
+	PyprojectVersionFile = "pyproject"
+	// PlainVersionFile contains nothing but the version
+	PlainVersionFile = "plain"
+	// RegexVersionFile is any file, the version is located by a regular expression
+	RegexVersionFile = "regex"
+)
+
+var (
+	goVersionRegexp          = regexp.MustCompile(`(?m)^\s*(?:const\s+)?VERSION\s*(?:string\s*)?=\s*"([^"]*)"`)
+	packageJSONVersionRegexp = regexp.MustCompile(`"version"\s*:\s*"([^"]*)"`)
+	plainVersionRegexp       = regexp.MustCompile(`\A\s*(\S+)`)
+	tomlSectionRegexp        = regexp.MustCompile(`(?m)^\s*\[([^\[\]]+)\]\s*(?:#.*)?$`)
+	tomlVersionRegexp        = regexp.MustCompile(`(?m)^\s*version\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+)
+
+//=======================================
+// Models
+//=======================================
+
+// VersionFile is a file storing the version of the project, read and updated in place.
+// The build number of the mobile project files (plist, pbxproj, gradle) is incremented on every update.
+type VersionFile struct {
+	Path string `yaml:"path"`
+	// Type of the file, detected from the file name if empty
+	Type string `yaml:"type,omitempty"`
+	// Pattern of the regex type: the version is the capturing group named version, or the first capturing group
+	Pattern string `yaml:"pattern,omitempty"`
+}
+
+// replacement replaces the content between start and end offsets with the value.
+type replacement struct {
+	start int
+	end   int
+	value string
+}
+
+//=======================================
+// Utility
+//=======================================
+
+// replace applies the (non overlapping) replacements, from the end of the content,
+// so the offsets of the remaining replacements stay valid.
+func replace(content string, replacements []replacement) string {
+	sort.Slice(replacements, func(i, j int) bool {
+		return replacements[i].start > replacements[j].start
+	})
+	for _, r := range replacements {
+		content = content[:r.start] + r.value + content[r.end:]
+	}
+	return content
+}
+
+func (file VersionFile) fileType() string {
+	if file.Type != "" {
+		return file.Type
+	}
+
+	// a pattern is a regex version file, whatever the file name is (e.g. a version constant in a .go file)
+	if file.Pattern != "" {
+		return RegexVersionFile
+	}
+
+	switch name := filepath.Base(file.Path); {
+	case name == "package.json":
+		return PackageJSONVersionFile
+	case name == "Cargo.toml":
+		return CargoVersionFile
+	case name == "pyproject.toml":
+		return PyprojectVersionFile
+	case strings.HasSuffix(name, ".go"):
+		return GoVersionFile
+	case strings.HasSuffix(name, ".plist"):
+		return PlistVersionFile
+	case strings.HasSuffix(name, ".pbxproj"):
+		return PbxprojVersionFile
+	case name == "build.gradle" || name == "build.gradle.kts":
+		return GradleVersionFile
+	}
+	return PlainVersionFile
+}
+
+// submatchLocations returns the location of the capturing group of every match.
+func submatchLocations(re *regexp.Regexp, content string, group int) [][]int {
+	locs := [][]int{}
+	for _, loc := range re.FindAllStringSubmatchIndex(content, -1) {
+		if loc[2*group] != -1 {
+			locs = append(locs, loc[2*group:2*group+2])
+		}
+	}
+	return locs
+}
+
+// submatchLocation returns the location of the first non empty capturing group of the first match.
+func submatchLocation(re *regexp.Regexp, content string, group int) ([]int, bool) {
+	loc := re.FindStringSubmatchIndex(content)
+	if loc == nil {
+		return nil, false
+	}
+
+	if group > 0 {
+		return loc[2*group : 2*group+2], loc[2*group] != -1
+	}
+	for idx := 1; idx < len(loc)/2; idx++ {
+		if loc[2*idx] != -1 {
+			return loc[2*idx : 2*idx+2], true
+		}
+	}
+	return nil, false
+}
+
+// tomlSectionVersionLocation returns the location of the version key's value in the first existing section.
+func tomlSectionVersionLocation(content string, sections ...string) ([]int, bool) {
+	headers := tomlSectionRegexp.FindAllStringSubmatchIndex(content, -1)
+
+	for _, section := range sections {
+		for idx, header := range headers {
+			if strings.TrimSpace(content[header[2]:header[3]]) != section {
+				continue
+			}
+
+			start, end := header[1], len(content)
+			if idx+1 < len(headers) {
+				end = headers[idx+1][0]
+			}
+
+			if loc, ok := submatchLocation(tomlVersionRegexp, content[start:end], 0); ok {
+				return []int{start + loc[0], start + loc[1]}, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// locate returns the start and end offsets of the version in the content of the file,
+// the mobile project files can store the version at several locations.
+func (file VersionFile) locate(content string) ([][]int, error) {
+	var loc []int
+	ok := false
+
+	switch file.fileType() {
+	case GoVersionFile:
+		loc, ok = submatchLocation(goVersionRegexp, content, 1)
+	case PackageJSONVersionFile:
+		loc, ok = submatchLocation(packageJSONVersionRegexp, content, 1)
+		if ok {
+			// the first version key has to be the package's version, not a nested one
+			var packageJSON struct {
+				Version string `json:"version"`
+			}
+			if err := json.Unmarshal([]byte(content), &packageJSON); err != nil {
+				return nil, err
+			}
+			ok = packageJSON.Version != "" && packageJSON.Version == content[loc[0]:loc[1]]
+		}
+	case CargoVersionFile:
+		loc, ok = tomlSectionVersionLocation(content, "package", "workspace.package")
+	case PyprojectVersionFile:
+		loc, ok = tomlSectionVersionLocation(content, "project", "tool.poetry")
+	case PlainVersionFile:
+		loc, ok = submatchLocation(plainVersionRegexp, content, 1)
+	case RegexVersionFile:
+		re, err := regexp.Compile(file.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid version pattern (%s), error: %s", file.Pattern, err)
+		}
+		if re.NumSubexp() == 0 {
+			return nil, fmt.Errorf("Invalid version pattern (%s): no capturing group", file.Pattern)
+		}
+		group := 1
+		for idx, name := range re.SubexpNames() {
+			if name == "version" {
+				group = idx
+			}
+		}
+		loc, ok = submatchLocation(re, content, group)
+	case PlistVersionFile, PbxprojVersionFile, GradleVersionFile:
+		locs := submatchLocations(mobileVersionRegexps[file.fileType()], content, 1)
+		if len(locs) > 0 {
+			return locs, nil
+		}
+	default:
+		return nil, fmt.Errorf("Invalid version file type (%s)", file.Type)
+	}
+
+	if !ok {
+		return nil, fmt.Errorf("Version not found in %s file (%s)", file.fileType(), file.Path)
+	}
+	return [][]int{loc}, nil
+}
+
+//=======================================
+// Main
+//=======================================
+
+// Validate ...
+func (file VersionFile) Validate() error {
+	if file.Path == "" {
+		return fmt.Errorf("Invalid version file: missing path")
+	}
+	switch file.fileType() {
+	case GoVersionFile, PackageJSONVersionFile, CargoVersionFile, PyprojectVersionFile, PlainVersionFile:
+	case PlistVersionFile, PbxprojVersionFile, GradleVersionFile:
+	case RegexVersionFile:
+		if file.Pattern == "" {
+			return fmt.Errorf("Invalid version file (%s): missing pattern", file.Path)
+		}
+	default:
+		return fmt.Errorf("Invalid version file (%s): unknown type (%s)", file.Path, file.Type)
+	}
+	return nil
+}
+
+// ReadVersion returns the version stored in the file.
+func (file VersionFile) ReadVersion() (string, error) {
+	contentBytes, err := readFile(file.Path)
+	if err != nil {
+		return "", err
+	}
+	content := string(contentBytes)
+
+	locs, err := file.locate(content)
+	if err != nil {
+		return "", err
+	}
+
+	version := content[locs[0][0]:locs[0][1]]
+	for _, loc := range locs[1:] {
+		if other := content[loc[0]:loc[1]]; other != version {
+			return "", fmt.Errorf("Versions are out of sync in %s: %s and %s", file.Path, version, other)
+		}
+	}
+	return version, nil
+}
+
+// WriteVersion replaces the version in the file and increments the build number of the mobile project files,
+// the rest of the file is kept untouched.
+func (file VersionFile) WriteVersion(version string) error {
+	info, err := os.Stat(file.Path)
+	if err != nil {
+		return err
+	}
+	contentBytes, err := readFile(file.Path)
+	if err != nil {
+		return err
+	}
+	content := string(contentBytes)
+
+	locs, err := file.locate(content)
+	if err != nil {
+		return err
+	}
+	replacements := []replacement{}
+	for _, loc := range locs {
+		replacements = append(replacements, replacement{start: loc[0], end: loc[1], value: version})
+	}
+
+	buildNumberReplacements, err := file.incrementedBuildNumbers(content)
+	if err != nil {
+		return err
+	}
+	replacements = append(replacements, buildNumberReplacements...)
+
+	return writeFile(file.Path, replace(content, replacements), info.Mode())
+}
+
+// ReadVersionFiles returns the version stored in the files, which have to store the same version.
+func ReadVersionFiles(files []VersionFile) (string, error) {
+	version := ""
+	for idx, file := range files {
+		fileVersion, err := file.ReadVersion()
+		if err != nil {
+			return "", err
+		}
+		if idx > 0 && fileVersion != version {
+			return "", fmt.Errorf("Version files are out of sync: %s stores %s, while %s stores %s", files[0].Path, version, file.Path, fileVersion)
+		}
+		version = fileVersion
+	}
+	return version, nil
+}
+
+// WriteVersionFiles updates the version in every file.
+func WriteVersionFiles(files []VersionFile, version string) error {
+	for _, file := range files {
+		if err := file.WriteVersion(version); err != nil {
+			return fmt.Errorf("Failed to update version file (%s), error: %s", file.Path, err)
+		}
+	}
+	return nil
+}
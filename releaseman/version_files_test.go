@@ -0,0 +1,101 @@
+package releaseman
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionFiles(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "releaseman")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, os.RemoveAll(tmpDir))
+	}()
+
+	testVersionFile := func(file VersionFile, content, currentVersion, updatedContent string) {
+		file.Path = filepath.Join(tmpDir, file.Path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(file.Path), 0755))
+		require.NoError(t, ioutil.WriteFile(file.Path, []byte(content), 0644))
+		require.NoError(t, file.Validate())
+
+		version, err := file.ReadVersion()
+		require.NoError(t, err)
+		require.Equal(t, currentVersion, version)
+
+		require.NoError(t, file.WriteVersion("1.3.0-rc.1"))
+		contentBytes, err := ioutil.ReadFile(file.Path)
+		require.NoError(t, err)
+		require.Equal(t, updatedContent, string(contentBytes))
+	}
+
+	t.Log("go")
+	{
+		content := "package version\n\n// VERSION ...\nconst VERSION = \"0.9.4\"\n"
+		testVersionFile(VersionFile{Path: "version/version.go"}, content, "0.9.4", strings.Replace(content, "0.9.4", "1.3.0-rc.1", 1))
+
+		content = "package version\n\nconst (\n\tNAME    = \"releaseman\"\n\tVERSION = \"0.9.4\"\n)\n"
+		testVersionFile(VersionFile{Path: "const.go", Type: GoVersionFile}, content, "0.9.4", strings.Replace(content, "0.9.4", "1.3.0-rc.1", 1))
+	}
+
+	t.Log("package.json")
+	{
+		content := "{\n    \"name\": \"app\",\n    \"version\": \"1.2.0\",\n    \"engines\": {\"node\": \">=18\"}\n}\n"
+		testVersionFile(VersionFile{Path: "web/package.json"}, content, "1.2.0", strings.Replace(content, "1.2.0", "1.3.0-rc.1", 1))
+	}
+
+	t.Log("Cargo.toml")
+	{
+		content := "[dependencies]\nserde = { version = \"1.0\" }\n\n[package]\nname = \"app\"\nversion = \"1.2.0\" # the app version\n"
+		testVersionFile(VersionFile{Path: "Cargo.toml"}, content, "1.2.0", strings.Replace(content, "\"1.2.0\"", "\"1.3.0-rc.1\"", 1))
+	}
+
+	t.Log("pyproject.toml")
+	{
+		content := "[build-system]\nrequires = [\"setuptools\"]\n\n[project]\nname = 'app'\nversion = '1.2.0'\n"
+		testVersionFile(VersionFile{Path: "pyproject.toml"}, content, "1.2.0", strings.Replace(content, "1.2.0", "1.3.0-rc.1", 1))
+	}
+
+	t.Log("plain")
+	{
+		testVersionFile(VersionFile{Path: "VERSION"}, "1.2.0\n", "1.2.0", "1.3.0-rc.1\n")
+	}
+
+	t.Log("regex")
+	{
+		content := "MARKETING_VERSION: 1.2.0\nBUILD: 12\n"
+		file := VersionFile{Path: "app.yml", Pattern: `BUILD: \d+|MARKETING_VERSION: (?P<version>\S+)`}
+		testVersionFile(file, content, "1.2.0", "MARKETING_VERSION: 1.3.0-rc.1\nBUILD: 12\n")
+
+		// the pattern wins over the file extension
+		content = "package version\n\nvar buildVersion = \"1.2.0\"\n"
+		file = VersionFile{Path: "build.go", Pattern: `buildVersion = "(?P<version>[^"]+)"`}
+		testVersionFile(file, content, "1.2.0", strings.Replace(content, "1.2.0", "1.3.0-rc.1", 1))
+	}
+
+	t.Log("version not found")
+	{
+		path := filepath.Join(tmpDir, "package.json")
+		require.NoError(t, ioutil.WriteFile(path, []byte(`{"name": "app", "dependencies": {"x": {"version": "1.0.0"}}}`), 0644))
+		_, err := VersionFile{Path: path}.ReadVersion()
+		require.Error(t, err)
+
+		require.Error(t, VersionFile{Path: "app.yml", Type: RegexVersionFile}.Validate())
+		require.Error(t, VersionFile{Path: "app.xml", Type: "xml"}.Validate())
+	}
+
+	t.Log("files out of sync")
+	{
+		version, err := ReadVersionFiles([]VersionFile{{Path: filepath.Join(tmpDir, "VERSION")}, {Path: filepath.Join(tmpDir, "Cargo.toml")}})
+		require.NoError(t, err)
+		require.Equal(t, "1.3.0-rc.1", version)
+
+		require.NoError(t, ioutil.WriteFile(filepath.Join(tmpDir, "VERSION"), []byte("1.2.0"), 0644))
+		_, err = ReadVersionFiles([]VersionFile{{Path: filepath.Join(tmpDir, "VERSION")}, {Path: filepath.Join(tmpDir, "Cargo.toml")}})
+		require.Error(t, err)
+	}
+}
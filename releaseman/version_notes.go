@@ -0,0 +1,70 @@
+package releaseman
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bitrise-tools/releaseman/git"
+)
+
+//=======================================
+// Main
+//=======================================
+
+// NewVersionChangelog returns the changelog of a single version: the commits reachable from the endCommit,
+// but not from the startCommit (nil means the initial commit). The version names the end commit, if it is set.
+func NewVersionChangelog(commits []git.CommitModel, startCommit *git.CommitModel, endCommit git.CommitModel, version string, config Config) ChangelogModel {
+	if version != "" {
+		endCommit.Version = version
+		endCommit.Tag = config.Release.TagFormat.Tag(version)
+	}
+
+	startTaggedCommit := git.CommitModel{}
+	if startCommit != nil {
+		startTaggedCommit = *startCommit
+	}
+
+	contentItems := []ChangelogContentItemModel{
+		ChangelogContentItemModel{
+			StartTaggedCommit: startTaggedCommit,
+			EndTaggedCommit:   endCommit,
+			Commits:           commitsBetween(startCommit, &endCommit, commits),
+		},
+	}
+
+	return ChangelogModel{
+		ContentItems: groupContentItems(contentItems, config),
+		Version:      endCommit.Version,
+		CurrentDate:  time.Now(),
+	}
+}
+
+// RenderVersionNotes renders the changelog section of a single version changelog (see NewVersionChangelog),
+// with the content template, or in the keepachangelog format.
+func RenderVersionNotes(changelog ChangelogModel, config Config) (string, error) {
+	if config.Changelog.Format == KeepAChangelogFormat {
+		release, err := newKeepAChangelogRelease(changelog.ContentItems[0])
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(ChangelogDocument{Releases: []ChangelogReleaseSection{release}}.String()), nil
+	}
+
+	content, err := renderChangelogContent(changelog, config)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(content), nil
+}
+
+// ExtractVersionNotes returns the section of the version from the changelog.
+func ExtractVersionNotes(changelog, version string) (string, error) {
+	document := ParseChangelogDocument(changelog)
+
+	idx := document.Release(version)
+	if idx == -1 {
+		return "", fmt.Errorf("The changelog does not contain the section of version (%s)", version)
+	}
+	return strings.TrimSpace(ChangelogDocument{Releases: document.Releases[idx : idx+1]}.String()), nil
+}
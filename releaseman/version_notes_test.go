@@ -0,0 +1,65 @@
+package releaseman
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bitrise-tools/releaseman/git"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionNotes(t *testing.T) {
+	date := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	// 1 (tag: v1.0.0) <- 2 <- 3 (tag: v1.1.0) <- 4
+	startCommit := git.CommitModel{Hash: "1", Tag: "v1.0.0", Version: "1.0.0", Date: date}
+	endCommit := git.CommitModel{Hash: "3", Parents: []string{"2"}, Tag: "v1.1.0", Version: "1.1.0", Date: date, Type: "fix", Description: "crash"}
+	commits := []git.CommitModel{
+		git.CommitModel{Hash: "4", Parents: []string{"3"}, Type: "feat", Description: "unreleased"},
+		endCommit,
+		git.CommitModel{Hash: "2", Parents: []string{"1"}, Type: "feat", Description: "dark mode"},
+		startCommit,
+	}
+	config := Config{
+		Release:   Release{TagFormat: "v{{.Version}}"},
+		Changelog: Changelog{Sections: DefaultChangelogSections},
+	}
+
+	t.Log("content template")
+	{
+		changelog := NewVersionChangelog(commits, &startCommit, endCommit, "", config)
+		require.Equal(t, "1.1.0", changelog.Version)
+
+		notes, err := RenderVersionNotes(changelog, config)
+		require.NoError(t, err)
+		require.Equal(t, "### v1.1.0 (2026 Oct 16)\n\n#### Features\n\n* [2] dark mode\n\n#### Bug Fixes\n\n* [3] crash", notes)
+	}
+
+	t.Log("keepachangelog")
+	{
+		config := config
+		config.Changelog.Format = KeepAChangelogFormat
+		notes, err := RenderVersionNotes(NewVersionChangelog(commits, &startCommit, endCommit, "", config), config)
+		require.NoError(t, err)
+		require.Equal(t, "## [1.1.0] - 2026-10-16\n\n### Features\n\n- dark mode\n\n### Bug Fixes\n\n- crash", notes)
+	}
+
+	t.Log("commit range named by the version")
+	{
+		changelog := NewVersionChangelog(commits, &startCommit, commits[0], "1.2.0", config)
+		require.Equal(t, "v1.2.0", changelog.ContentItems[0].EndTaggedCommit.Tag)
+		require.Equal(t, 3, len(changelog.ContentItems[0].Commits))
+
+		changelog = NewVersionChangelog(commits, nil, endCommit, "", config)
+		require.Equal(t, 3, len(changelog.ContentItems[0].Commits))
+	}
+
+	t.Log("extracted from the changelog")
+	{
+		notes, err := ExtractVersionNotes(testKeepAChangelog, "1.0.0")
+		require.NoError(t, err)
+		require.Equal(t, "## [1.0.0] - 2026-01-02\n\n### Fixed\n\n- crash on start", notes)
+
+		_, err = ExtractVersionNotes(testKeepAChangelog, "2.0.0")
+		require.Error(t, err)
+	}
+}
@@ -0,0 +1,222 @@
+package versioning
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//=======================================
+// Consts
+//=======================================
+
+// DefaultCalVerFormat ...
+const DefaultCalVerFormat = "YYYY.MM.MICRO"
+
+const microToken = "MICRO"
+
+// calVerTokens are the supported date segments, see: https://calver.org
+var calVerTokens = map[string]bool{
+	"YYYY": true, "YY": true, "0Y": true,
+	"MM": true, "0M": true,
+	"WW": true, "0W": true,
+	"DD": true, "0D": true,
+}
+
+//=======================================
+// Models
+//=======================================
+
+// CalVer is the calendar versioning scheme, e.g. YYYY.MM.MICRO or YY.0M.DD.
+// Every version bump means the next calendar release: the date of the release,
+// and the MICRO counter of the releases in the same period (starting from 0).
+type CalVer struct {
+	Format string
+	tokens []string
+}
+
+//=======================================
+// Utility
+//=======================================
+
+func (scheme CalVer) hasMicro() bool {
+	return scheme.tokens[len(scheme.tokens)-1] == microToken
+}
+
+// dateSegments returns the date segments of the date, in the format of the scheme.
+func (scheme CalVer) dateSegments(date time.Time) []int {
+	year, week := date.ISOWeek()
+	if !strings.Contains(scheme.Format, "W") {
+		year = date.Year()
+	}
+
+	segments := []int{}
+	for _, token := range scheme.tokens {
+		switch token {
+		case "YYYY":
+			segments = append(segments, year)
+		case "YY", "0Y":
+			segments = append(segments, year-2000)
+		case "MM", "0M":
+			segments = append(segments, int(date.Month()))
+		case "WW", "0W":
+			segments = append(segments, week)
+		case "DD", "0D":
+			segments = append(segments, date.Day())
+		}
+	}
+	return segments
+}
+
+func (scheme CalVer) format(segments []int) string {
+	parts := []string{}
+	for idx, token := range scheme.tokens {
+		if strings.HasPrefix(token, "0") {
+			parts = append(parts, fmt.Sprintf("%02d", segments[idx]))
+		} else {
+			parts = append(parts, strconv.Itoa(segments[idx]))
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// parse returns the segments of the version, the build metadata (+...) is ignored.
+func (scheme CalVer) parse(versionStr string) ([]int, error) {
+	versionStr = strings.SplitN(versionStr, "+", 2)[0]
+
+	parts := strings.Split(versionStr, ".")
+	if len(parts) != len(scheme.tokens) {
+		return []int{}, fmt.Errorf("Malformed version: %s, does not match format: %s", versionStr, scheme.Format)
+	}
+
+	segments := []int{}
+	for idx, part := range parts {
+		token := scheme.tokens[idx]
+
+		segment, err := strconv.Atoi(part)
+		if err != nil || segment < 0 || strings.HasPrefix(part, "+") {
+			return []int{}, fmt.Errorf("Malformed version: %s, segment (%s) is not a number", versionStr, part)
+		}
+
+		valid := true
+		switch token {
+		case "YYYY":
+			valid = len(part) == 4
+		case "0Y", "0M", "0W", "0D":
+			valid = len(part) == 2
+		default:
+			valid = part == strconv.Itoa(segment)
+		}
+		switch token {
+		case "MM", "0M":
+			valid = valid && segment >= 1 && segment <= 12
+		case "WW", "0W":
+			valid = valid && segment >= 1 && segment <= 53
+		case "DD", "0D":
+			valid = valid && segment >= 1 && segment <= 31
+		}
+		if !valid {
+			return []int{}, fmt.Errorf("Malformed version: %s, invalid %s segment (%s)", versionStr, token, part)
+		}
+
+		segments = append(segments, segment)
+	}
+	return segments, nil
+}
+
+//=======================================
+// Main
+//=======================================
+
+// NewCalVer returns the calendar versioning scheme of the format (DefaultCalVerFormat if empty).
+// The format is a dot separated list of the YYYY, YY, 0Y, MM, 0M, WW, 0W, DD, 0D segments,
+// optionally ending with the MICRO counter.
+func NewCalVer(format string) (CalVer, error) {
+	if format == "" {
+		format = DefaultCalVerFormat
+	}
+
+	tokens := strings.Split(format, ".")
+	for idx, token := range tokens {
+		if token == microToken && idx == len(tokens)-1 && idx > 0 {
+			continue
+		}
+		if !calVerTokens[token] {
+			return CalVer{}, fmt.Errorf("Invalid calendar version format (%s): unsupported segment (%s)", format, token)
+		}
+	}
+
+	return CalVer{Format: format, tokens: tokens}, nil
+}
+
+// Validate ...
+func (scheme CalVer) Validate(versionStr string) error {
+	_, err := scheme.parse(versionStr)
+	return err
+}
+
+// Compare ...
+func (scheme CalVer) Compare(v1, v2 string) (int, error) {
+	segments1, err := scheme.parse(v1)
+	if err != nil {
+		return 0, err
+	}
+	segments2, err := scheme.parse(v2)
+	if err != nil {
+		return 0, err
+	}
+
+	for idx := range segments1 {
+		if segments1[idx] < segments2[idx] {
+			return -1, nil
+		}
+		if segments1[idx] > segments2[idx] {
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+// Bump returns the next calendar release, the bump kind does not matter, except the pre-release kinds are not supported.
+func (scheme CalVer) Bump(versionStr, kind string, options BumpOptions) (string, error) {
+	switch kind {
+	case PrereleaseBump, PremajorBump, PreminorBump, PrepatchBump, ReleaseBump:
+		return "", fmt.Errorf("Version bump (%s) is not supported by calendar versioning", kind)
+	}
+
+	segments, err := scheme.parse(versionStr)
+	if err != nil {
+		return "", err
+	}
+
+	next := scheme.dateSegments(options.date())
+	if scheme.hasMicro() {
+		next = append(next, 0)
+
+		sameDate := true
+		for idx := 0; idx < len(next)-1; idx++ {
+			sameDate = sameDate && next[idx] == segments[idx]
+		}
+		if sameDate {
+			next[len(next)-1] = segments[len(segments)-1] + 1
+		}
+	}
+
+	nextVersion := scheme.format(next)
+	if cmp, err := scheme.Compare(nextVersion, versionStr); err != nil {
+		return "", err
+	} else if cmp <= 0 {
+		return "", fmt.Errorf("Next calendar version (%s) is not greater than the current version (%s)", nextVersion, versionStr)
+	}
+	return nextVersion, nil
+}
+
+// First returns the calendar version of the release date.
+func (scheme CalVer) First(options BumpOptions) string {
+	next := scheme.dateSegments(options.date())
+	if scheme.hasMicro() {
+		next = append(next, 0)
+	}
+	return scheme.format(next)
+}
@@ -0,0 +1,134 @@
+package versioning
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCalVer(t *testing.T) {
+	scheme, err := NewCalVer("")
+	require.NoError(t, err)
+	require.Equal(t, DefaultCalVerFormat, scheme.Format)
+
+	_, err = NewCalVer("YY.0M.DD")
+	require.NoError(t, err)
+
+	_, err = NewCalVer("YYYY.MINOR.MICRO")
+	require.Error(t, err)
+
+	_, err = NewCalVer("MICRO.YYYY")
+	require.Error(t, err)
+
+	_, err = New("calver", "YYYY.0W")
+	require.NoError(t, err)
+
+	_, err = New("datever", "")
+	require.Error(t, err)
+}
+
+func TestCalVerValidate(t *testing.T) {
+	scheme, err := NewCalVer("YYYY.MM.MICRO")
+	require.NoError(t, err)
+	require.NoError(t, scheme.Validate("2024.1.0"))
+	require.NoError(t, scheme.Validate("2024.12.13+abc1234"))
+	require.Error(t, scheme.Validate("2024.01.0"))
+	require.Error(t, scheme.Validate("2024.13.0"))
+	require.Error(t, scheme.Validate("24.1.0"))
+	require.Error(t, scheme.Validate("2024.1"))
+	require.Error(t, scheme.Validate("1.2.3-rc.1"))
+
+	scheme, err = NewCalVer("YY.0M.0D")
+	require.NoError(t, err)
+	require.NoError(t, scheme.Validate("24.01.09"))
+	require.Error(t, scheme.Validate("24.1.9"))
+	require.Error(t, scheme.Validate("24.01.32"))
+}
+
+func TestCalVerCompare(t *testing.T) {
+	scheme, err := NewCalVer("YYYY.MM.MICRO")
+	require.NoError(t, err)
+
+	cmp, err := scheme.Compare("2024.10.0", "2024.9.3")
+	require.NoError(t, err)
+	require.Equal(t, 1, cmp)
+
+	cmp, err = scheme.Compare("2024.10.1", "2024.10.10")
+	require.NoError(t, err)
+	require.Equal(t, -1, cmp)
+
+	cmp, err = scheme.Compare("2024.10.1+abc1234", "2024.10.1")
+	require.NoError(t, err)
+	require.Equal(t, 0, cmp)
+
+	_, err = scheme.Compare("1.2.3-rc.1", "2024.10.1")
+	require.Error(t, err)
+}
+
+func TestCalVerBump(t *testing.T) {
+	date := time.Date(2024, time.October, 9, 12, 0, 0, 0, time.UTC)
+
+	t.Log("next release in the same period")
+	{
+		scheme, err := NewCalVer("YYYY.MM.MICRO")
+		require.NoError(t, err)
+
+		ver, err := scheme.Bump("2024.10.0", PatchBump, BumpOptions{Date: date})
+		require.NoError(t, err)
+		require.Equal(t, "2024.10.1", ver)
+
+		ver, err = scheme.Bump("2024.10.1+abc1234", MajorBump, BumpOptions{Date: date})
+		require.NoError(t, err)
+		require.Equal(t, "2024.10.2", ver)
+	}
+
+	t.Log("first release of the period")
+	{
+		scheme, err := NewCalVer("YYYY.MM.MICRO")
+		require.NoError(t, err)
+
+		ver, err := scheme.Bump("2024.9.4", MinorBump, BumpOptions{Date: date})
+		require.NoError(t, err)
+		require.Equal(t, "2024.10.0", ver)
+		require.Equal(t, "2024.10.0", scheme.First(BumpOptions{Date: date}))
+	}
+
+	t.Log("date only format")
+	{
+		scheme, err := NewCalVer("YY.0M.0D")
+		require.NoError(t, err)
+
+		ver, err := scheme.Bump("24.09.30", PatchBump, BumpOptions{Date: date})
+		require.NoError(t, err)
+		require.Equal(t, "24.10.09", ver)
+
+		_, err = scheme.Bump("24.10.09", PatchBump, BumpOptions{Date: date})
+		require.Error(t, err)
+	}
+
+	t.Log("week format")
+	{
+		scheme, err := NewCalVer("YYYY.0W.MICRO")
+		require.NoError(t, err)
+		require.Equal(t, "2024.41.0", scheme.First(BumpOptions{Date: date}))
+	}
+
+	t.Log("pre-releases are not supported")
+	{
+		scheme, err := NewCalVer("YYYY.MM.MICRO")
+		require.NoError(t, err)
+
+		_, err = scheme.Bump("2024.10.0", PrereleaseBump, BumpOptions{Date: date})
+		require.Error(t, err)
+	}
+
+	t.Log("current version from the future")
+	{
+		scheme, err := NewCalVer("YYYY.MM.MICRO")
+		require.NoError(t, err)
+
+		_, err = scheme.Bump("2025.1.0", PatchBump, BumpOptions{Date: date})
+		require.Error(t, err)
+	}
+}
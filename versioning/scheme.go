@@ -0,0 +1,83 @@
+package versioning
+
+import (
+	"fmt"
+	"time"
+)
+
+//=======================================
+// Consts
+//=======================================
+
+const (
+	// SemVerScheme ...
+	SemVerScheme = "semver"
+	// CalVerScheme ...
+	CalVerScheme = "calver"
+)
+
+const (
+	// MajorBump ...
+	MajorBump = "major"
+	// MinorBump ...
+	MinorBump = "minor"
+	// PatchBump ...
+	PatchBump = "patch"
+	// PrereleaseBump ...
+	PrereleaseBump = "prerelease"
+	// PremajorBump ...
+	PremajorBump = "premajor"
+	// PreminorBump ...
+	PreminorBump = "preminor"
+	// PrepatchBump ...
+	PrepatchBump = "prepatch"
+	// ReleaseBump ...
+	ReleaseBump = "release"
+)
+
+//=======================================
+// Models
+//=======================================
+
+// BumpOptions ...
+type BumpOptions struct {
+	// PrereleaseIdentifier of the pre-release bumps, defaults to rc
+	PrereleaseIdentifier string
+	// Date of the release, defaults to now
+	Date time.Time
+}
+
+func (options BumpOptions) date() time.Time {
+	if options.Date.IsZero() {
+		return time.Now()
+	}
+	return options.Date
+}
+
+// Scheme is a versioning scheme: it validates, orders and bumps the versions.
+type Scheme interface {
+	// Validate returns an error if the version is not valid in the scheme.
+	Validate(version string) error
+	// Compare returns -1, 0 or 1 if v1 is lower than, equal to or greater than v2.
+	Compare(v1, v2 string) (int, error)
+	// Bump returns the next version of the bump kind.
+	Bump(version, kind string, options BumpOptions) (string, error)
+	// First returns the version of the first release.
+	First(options BumpOptions) string
+}
+
+//=======================================
+// Main
+//=======================================
+
+// New returns the versioning scheme of the name (semver by default),
+// the format is used by the calver scheme only.
+func New(name, format string) (Scheme, error) {
+	switch name {
+	case "", SemVerScheme:
+		return SemVer{}, nil
+	case CalVerScheme:
+		return NewCalVer(format)
+	}
+	return nil, fmt.Errorf("Invalid version scheme (%s), available schemes: %s, %s", name, SemVerScheme, CalVerScheme)
+}
@@ -0,0 +1,175 @@
+package versioning
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	version "github.com/hashicorp/go-version"
+)
+
+//=======================================
+// Consts
+//=======================================
+
+const (
+	defaultPrereleaseIdentifier = "rc"
+	firstSemVer                 = "0.0.1"
+)
+
+//=======================================
+// Models
+//=======================================
+
+// SemVer is the semantic versioning scheme, see: https://semver.org
+type SemVer struct{}
+
+//=======================================
+// Utility
+//=======================================
+
+// versionCore returns the dot separated segments of the version, without pre-release and build metadata.
+func versionCore(segments []int64) string {
+	parts := []string{}
+	for _, segment := range segments {
+		parts = append(parts, strconv.FormatInt(segment, 10))
+	}
+	return strings.Join(parts, ".")
+}
+
+func versionSegmentIdx(segmentStr string) (int, error) {
+	segmentIdx := 0
+	switch segmentStr {
+	case PatchBump:
+		segmentIdx = 2
+	case MinorBump:
+		segmentIdx = 1
+	case MajorBump:
+		segmentIdx = 0
+	default:
+		return -1, fmt.Errorf("Invalid segment name (%s)", segmentStr)
+	}
+	return segmentIdx, nil
+}
+
+func bumpedVersion(versionStr string, segmentIdx int) (string, error) {
+	if segmentIdx < 0 {
+		return "", fmt.Errorf("Invalid (negative) segment index: %d", segmentIdx)
+	}
+
+	ver, err := version.NewVersion(versionStr)
+	if err != nil {
+		return "", err
+	}
+	verSegments := ver.Segments64()
+	if segmentIdx > len(verSegments)-1 {
+		return "", fmt.Errorf("Version does not have enough segments (segments count: %d) to increment segment at idx (%d)", len(verSegments), segmentIdx)
+	}
+	// Segments64 can be used for changing segments, but Segments() can't!!
+	//  See: https://github.com/hashicorp/go-version/issues/24
+	ver.Segments64()[segmentIdx] = verSegments[segmentIdx] + 1
+	// the lower segments restart from zero, like in the pre-releases: 1.4.2 -> 1.5.0, 2.0.0
+	for idx := segmentIdx + 1; idx < len(verSegments); idx++ {
+		ver.Segments64()[idx] = 0
+	}
+
+	// The pre-release and the build metadata belong to the previous version
+	return versionCore(ver.Segments64()), nil
+}
+
+// bumpedPrereleaseVersion bumps the version to a pre-release or releases the pre-release:
+//   - premajor, preminor, prepatch: 1.2.0 -> 2.0.0-rc.1, 1.3.0-rc.1, 1.2.1-rc.1
+//   - prerelease: 1.3.0-rc.1 -> 1.3.0-rc.2, 1.3.0-beta.2 -> 1.3.0-rc.1, 1.2.0 -> 1.2.1-rc.1
+//   - release: 1.3.0-rc.2 -> 1.3.0
+func bumpedPrereleaseVersion(versionStr, kind, identifier string) (string, error) {
+	ver, err := version.NewVersion(versionStr)
+	if err != nil {
+		return "", err
+	}
+
+	if identifier == "" {
+		identifier = defaultPrereleaseIdentifier
+	}
+	firstPrerelease := identifier + ".1"
+
+	switch kind {
+	case PremajorBump, PreminorBump, PrepatchBump:
+		segment := map[string]string{PremajorBump: MajorBump, PreminorBump: MinorBump, PrepatchBump: PatchBump}[kind]
+		segmentIdx, err := versionSegmentIdx(segment)
+		if err != nil {
+			return "", err
+		}
+		core, err := bumpedVersion(versionStr, segmentIdx)
+		if err != nil {
+			return "", err
+		}
+		return core + "-" + firstPrerelease, nil
+	case PrereleaseBump:
+		pre := ver.Prerelease()
+		if pre == "" {
+			return bumpedPrereleaseVersion(versionStr, PrepatchBump, identifier)
+		}
+
+		parts := strings.Split(pre, ".")
+		if parts[0] != identifier {
+			return versionCore(ver.Segments64()) + "-" + firstPrerelease, nil
+		}
+
+		last := parts[len(parts)-1]
+		if number, err := strconv.ParseInt(last, 10, 64); err == nil && len(parts) > 1 {
+			parts[len(parts)-1] = strconv.FormatInt(number+1, 10)
+		} else {
+			parts = append(parts, "1")
+		}
+		return versionCore(ver.Segments64()) + "-" + strings.Join(parts, "."), nil
+	case ReleaseBump:
+		if ver.Prerelease() == "" {
+			return "", fmt.Errorf("Version (%s) is not a pre-release", versionStr)
+		}
+		return versionCore(ver.Segments64()), nil
+	}
+
+	return "", fmt.Errorf("Invalid pre-release bump (%s)", kind)
+}
+
+//=======================================
+// Main
+//=======================================
+
+// Validate ...
+func (scheme SemVer) Validate(versionStr string) error {
+	_, err := version.NewVersion(versionStr)
+	return err
+}
+
+// Compare ...
+func (scheme SemVer) Compare(v1, v2 string) (int, error) {
+	ver1, err := version.NewVersion(v1)
+	if err != nil {
+		return 0, err
+	}
+	ver2, err := version.NewVersion(v2)
+	if err != nil {
+		return 0, err
+	}
+	return ver1.Compare(ver2), nil
+}
+
+// Bump bumps the version by any of the bump kinds.
+func (scheme SemVer) Bump(versionStr, kind string, options BumpOptions) (string, error) {
+	switch kind {
+	case PrereleaseBump, PremajorBump, PreminorBump, PrepatchBump, ReleaseBump:
+		return bumpedPrereleaseVersion(versionStr, kind, options.PrereleaseIdentifier)
+	}
+
+	segmentIdx, err := versionSegmentIdx(kind)
+	if err != nil {
+		return "", err
+	}
+	return bumpedVersion(versionStr, segmentIdx)
+}
+
+// First ...
+func (scheme SemVer) First(options BumpOptions) string {
+	return firstSemVer
+}
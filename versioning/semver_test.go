@@ -0,0 +1,130 @@
+package versioning
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBumpVersion(t *testing.T) {
+	ver, err := bumpedVersion("", -1)
+	require.EqualError(t, err, "Invalid (negative) segment index: -1")
+	require.Equal(t, "", ver)
+
+	ver, err = bumpedVersion("", 0)
+	require.EqualError(t, err, "Malformed version: ")
+	require.Equal(t, "", ver)
+
+	ver, err = bumpedVersion("-1", 0)
+	require.EqualError(t, err, "Malformed version: -1")
+	require.Equal(t, "", ver)
+
+	ver, err = bumpedVersion("1.0.0", 12)
+	require.EqualError(t, err, "Version does not have enough segments (segments count: 3) to increment segment at idx (12)")
+	require.Equal(t, "", ver)
+
+	ver, err = bumpedVersion("1", 0)
+	require.NoError(t, err)
+	require.Equal(t, "2.0.0", ver)
+
+	ver, err = bumpedVersion("1.1", 0)
+	require.NoError(t, err)
+	require.Equal(t, "2.0.0", ver)
+
+	ver, err = bumpedVersion("1.1", 1)
+	require.NoError(t, err)
+	require.Equal(t, "1.2.0", ver)
+
+	ver, err = bumpedVersion("1.1.1", 0)
+	require.NoError(t, err)
+	require.Equal(t, "2.0.0", ver)
+
+	ver, err = bumpedVersion("1.1.1", 1)
+	require.NoError(t, err)
+	require.Equal(t, "1.2.0", ver)
+
+	ver, err = bumpedVersion("1.4.2", 1)
+	require.NoError(t, err)
+	require.Equal(t, "1.5.0", ver)
+
+	ver, err = bumpedVersion("1.1.1", 2)
+	require.NoError(t, err)
+	require.Equal(t, "1.1.2", ver)
+
+	ver, err = bumpedVersion("1.3.0-rc.2+abc1234", 1)
+	require.NoError(t, err)
+	require.Equal(t, "1.4.0", ver)
+}
+
+func TestBumpPrereleaseVersion(t *testing.T) {
+	t.Log("pre-release of the next version")
+	{
+		ver, err := SemVer{}.Bump("1.2.0", PremajorBump, BumpOptions{})
+		require.NoError(t, err)
+		require.Equal(t, "2.0.0-rc.1", ver)
+
+		ver, err = SemVer{}.Bump("1.2.3", PreminorBump, BumpOptions{PrereleaseIdentifier: "beta"})
+		require.NoError(t, err)
+		require.Equal(t, "1.3.0-beta.1", ver)
+
+		ver, err = SemVer{}.Bump("1.2.0", PrepatchBump, BumpOptions{PrereleaseIdentifier: "rc"})
+		require.NoError(t, err)
+		require.Equal(t, "1.2.1-rc.1", ver)
+	}
+
+	t.Log("next pre-release")
+	{
+		ver, err := SemVer{}.Bump("1.3.0-rc.1", PrereleaseBump, BumpOptions{PrereleaseIdentifier: "rc"})
+		require.NoError(t, err)
+		require.Equal(t, "1.3.0-rc.2", ver)
+
+		ver, err = SemVer{}.Bump("1.3.0-rc.9+abc1234", PrereleaseBump, BumpOptions{PrereleaseIdentifier: "rc"})
+		require.NoError(t, err)
+		require.Equal(t, "1.3.0-rc.10", ver)
+
+		ver, err = SemVer{}.Bump("1.3.0-beta.2", PrereleaseBump, BumpOptions{PrereleaseIdentifier: "rc"})
+		require.NoError(t, err)
+		require.Equal(t, "1.3.0-rc.1", ver)
+
+		ver, err = SemVer{}.Bump("1.3.0-rc", PrereleaseBump, BumpOptions{PrereleaseIdentifier: "rc"})
+		require.NoError(t, err)
+		require.Equal(t, "1.3.0-rc.1", ver)
+
+		ver, err = SemVer{}.Bump("1.2.0", PrereleaseBump, BumpOptions{PrereleaseIdentifier: "rc"})
+		require.NoError(t, err)
+		require.Equal(t, "1.2.1-rc.1", ver)
+	}
+
+	t.Log("release")
+	{
+		ver, err := SemVer{}.Bump("1.3.0-rc.2", ReleaseBump, BumpOptions{PrereleaseIdentifier: "rc"})
+		require.NoError(t, err)
+		require.Equal(t, "1.3.0", ver)
+
+		_, err = SemVer{}.Bump("1.3.0", ReleaseBump, BumpOptions{PrereleaseIdentifier: "rc"})
+		require.Error(t, err)
+	}
+
+	t.Log("invalid bump")
+	{
+		_, err := SemVer{}.Bump("1.3.0", "postrelease", BumpOptions{PrereleaseIdentifier: "rc"})
+		require.Error(t, err)
+	}
+}
+
+func TestSemVerCompare(t *testing.T) {
+	cmp, err := SemVer{}.Compare("1.10.0", "1.9.0")
+	require.NoError(t, err)
+	require.Equal(t, 1, cmp)
+
+	cmp, err = SemVer{}.Compare("1.3.0-rc.2", "1.3.0")
+	require.NoError(t, err)
+	require.Equal(t, -1, cmp)
+
+	cmp, err = SemVer{}.Compare("1.3.0+abc1234", "1.3.0")
+	require.NoError(t, err)
+	require.Equal(t, 0, cmp)
+
+	_, err = SemVer{}.Compare("next", "1.3.0")
+	require.Error(t, err)
+}